@@ -0,0 +1,213 @@
+// Package battery 蓄电池组的跨轮询状态：按组电流判断充放电状态并累计放电
+// 容量，估算 SOC/SOH，跟踪单体内阻趋势。
+//
+// 本包不依赖 Extism，状态由驱动保存在 Extism var 中并在每次采集时传入，
+// 可在本机测试。
+package battery
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// 按组电流 current（充电为正）判断每组状态：
+//
+//	current < -DischargeA              放电
+//	-DischargeA <= current <= IdleA    静置
+//	IdleA < current <= FloatA          浮充
+//	current > FloatA                   充电
+//
+// 进入放电时记录开始时间，放电期间按相邻两次采集的平均放电电流累计容量（Ah）
+// 并记录最低单体电压；离开放电时返回该次放电记录。相邻两次采集超过
+// MaxIntegrateGap 秒的间隔不计容量。
+//
+// SOC 按组电流安时积分：SOC += current × Δt / CapacityAh（间隔规则同放电
+// 容量），限制在 0~100%。持续浮充 FloatMin 分钟置 100%；持续静置 RestMin
+// 分钟按平均单体电压在 OCVEmpty~OCVFull 间线性折算。首次采集 SOC 假定为
+// 100%，在第一次校准前可信度为“未校准”。
+
+// MaxIntegrateGap 相邻采集间隔超过此值（秒）时不累计容量
+const MaxIntegrateGap = 300
+
+// 充放电状态取值
+const (
+	StateIdle      = 0
+	StateFloat     = 1
+	StateCharge    = 2
+	StateDischarge = 3
+)
+
+// 可信度取值：SOC 为 未校准/推算/已校准，SOH 为 无基准/部分单体/全部单体
+const (
+	ConfNone    = 0
+	ConfPartial = 1
+	ConfFull    = 2
+)
+
+// Cell 一个单体的工程值
+type Cell struct {
+	No    int
+	Value float64
+}
+
+// CellNo 单体号至少两位，如 07
+func CellNo(no int) string {
+	if no < 10 {
+		return "0" + strconv.Itoa(no)
+	}
+	return strconv.Itoa(no)
+}
+
+// Stats 一类单体值的统计
+type Stats struct {
+	Max, Min, Mean float64
+	MaxNo, MinNo   int
+}
+
+// StatsOf 统计单体值，cells 不能为空
+func StatsOf(cells []Cell) Stats {
+	s := Stats{Max: cells[0].Value, Min: cells[0].Value, MaxNo: cells[0].No, MinNo: cells[0].No}
+	sum := 0.0
+	for _, c := range cells {
+		sum += c.Value
+		if c.Value > s.Max {
+			s.Max, s.MaxNo = c.Value, c.No
+		}
+		if c.Value < s.Min {
+			s.Min, s.MinNo = c.Value, c.No
+		}
+	}
+	s.Mean = sum / float64(len(cells))
+	return s
+}
+
+// Params 充放电门限与 SOC 估算参数
+type Params struct {
+	IdleA      float64 // 组电流绝对值不超过此值视为静置（A）
+	FloatA     float64 // 充电电流不超过此值视为浮充（A）
+	DischargeA float64 // 放电电流超过此值视为放电（A）
+	CapacityAh float64 // 每组额定容量（Ah），不大于 0 时不积分 SOC
+	FloatMin   float64 // 持续浮充多少分钟后 SOC 校准为 100%
+	RestMin    float64 // 持续静置多少分钟后按开路电压校准 SOC
+	OCVEmpty   float64 // SOC 0% 对应的单体开路电压（V）
+	OCVFull    float64 // SOC 100% 对应的单体开路电压（V）
+}
+
+// Classify 组电流对应的充放电状态
+func (p Params) Classify(current float64) int {
+	switch {
+	case current < -p.DischargeA:
+		return StateDischarge
+	case current > p.FloatA:
+		return StateCharge
+	case current > p.IdleA:
+		return StateFloat
+	}
+	return StateIdle
+}
+
+// State 一组跨轮询保存的状态
+type State struct {
+	State   int        `json:"state"`
+	Time    int64      `json:"time"`             // 上次读到组电流的时间（Unix 秒）
+	Current float64    `json:"current"`          // 上次组电流（充电为正）
+	Since   int64      `json:"since"`            // 进入当前状态的时间
+	Active  *Discharge `json:"active,omitempty"` // 进行中的放电
+	Last    *Discharge `json:"last,omitempty"`   // 上次完成的放电
+
+	SOC     *float64 `json:"soc,omitempty"` // 缺省视为 100%
+	SOCConf int      `json:"soc_conf"`
+	SOH     *float64 `json:"soh,omitempty"`      // 最近一次读到内阻时的 SOH
+	SOHTime int64    `json:"soh_time,omitempty"` // 最近一次估算 SOH 的时间
+}
+
+// Discharge 一次放电
+type Discharge struct {
+	Start   int64   `json:"start"` // Unix 秒
+	End     int64   `json:"end"`   // 进行中为最近一次采集时间
+	Ah      float64 `json:"ah"`
+	MinU    float64 `json:"min_cell_voltage"`
+	MinCell int     `json:"min_cell"` // 0 表示放电期间未读到单体电压
+}
+
+// Update 按本次组电流 current（充电为正）与单体电压 cellU（可为空）更新状态，
+// 放电结束时返回该次放电
+func (st *State) Update(p Params, current float64, cellU []Cell, now int64) *Discharge {
+	state := p.Classify(current)
+
+	if d := st.Active; d != nil {
+		if dt := now - st.Time; st.Time > 0 && dt > 0 && dt <= MaxIntegrateGap {
+			d.Ah += (math.Max(-st.Current, 0) + math.Max(-current, 0)) / 2 * float64(dt) / 3600
+		}
+		d.End = now
+	}
+	if state == StateDischarge {
+		if st.Active == nil {
+			st.Active = &Discharge{Start: now, End: now}
+		}
+		if len(cellU) > 0 {
+			if s := StatsOf(cellU); st.Active.MinCell == 0 || s.Min < st.Active.MinU {
+				st.Active.MinU, st.Active.MinCell = s.Min, s.MinNo
+			}
+		}
+	}
+
+	var done *Discharge
+	if state != StateDischarge && st.Active != nil {
+		d := *st.Active
+		d.Ah = math.Round(d.Ah*1000) / 1000
+		st.Last, st.Active, done = &d, nil, &d
+	}
+	if state != st.State || st.Since == 0 {
+		st.Since = now
+	}
+	st.countSOC(p, state, current, cellU, now)
+	st.State, st.Time, st.Current = state, now, current
+	return done
+}
+
+// countSOC 按本次组电流积分 SOC 并在浮充/静置足够久时校准；state 为本次状态，
+// st.Time/st.Current 仍为上次采集
+func (st *State) countSOC(p Params, state int, current float64, cellU []Cell, now int64) {
+	soc := 100.0
+	if st.SOC != nil {
+		soc = *st.SOC
+	}
+	if p.CapacityAh > 0 {
+		if dt := now - st.Time; st.Time > 0 && dt > 0 && dt <= MaxIntegrateGap {
+			soc += (st.Current + current) / 2 * float64(dt) / 3600 / p.CapacityAh * 100
+		}
+	}
+
+	calibrated := false
+	held := float64(now-st.Since) / 60
+	switch {
+	case state == StateFloat && held >= p.FloatMin:
+		soc, calibrated = 100, true
+	case state == StateIdle && held >= p.RestMin && len(cellU) > 0 && p.OCVFull > p.OCVEmpty:
+		soc, calibrated = (StatsOf(cellU).Mean-p.OCVEmpty)/(p.OCVFull-p.OCVEmpty)*100, true
+	}
+	switch {
+	case calibrated:
+		st.SOCConf = ConfFull
+	case st.SOCConf == ConfFull:
+		st.SOCConf = ConfPartial
+	}
+	soc = math.Max(0, math.Min(100, soc))
+	st.SOC = &soc
+}
+
+// median 中位数，values 为空时返回 0
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,154 @@
+package battery
+
+import (
+	"math"
+	"testing"
+)
+
+var testParams = Params{
+	IdleA: 0.5, FloatA: 3, DischargeA: 1,
+	CapacityAh: 100, FloatMin: 60, RestMin: 120, OCVEmpty: 1.98, OCVFull: 2.13,
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		current float64
+		want    int
+	}{
+		{-1.01, StateDischarge},
+		{-1, StateIdle}, // 门限本身不算放电
+		{0, StateIdle},
+		{0.5, StateIdle},
+		{0.51, StateFloat},
+		{3, StateFloat},
+		{3.01, StateCharge},
+	}
+	for _, tt := range tests {
+		if got := testParams.Classify(tt.current); got != tt.want {
+			t.Errorf("Classify(%v) = %d, want %d", tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateTransitions(t *testing.T) {
+	st := &State{}
+	steps := []struct {
+		now     int64
+		current float64
+		state   int
+		since   int64
+	}{
+		{1000, 0, StateIdle, 1000},
+		{1060, 2, StateFloat, 1060},
+		{1120, 2.5, StateFloat, 1060},
+		{1180, 10, StateCharge, 1180},
+		{1240, -20, StateDischarge, 1240},
+		{1300, 0.2, StateIdle, 1300},
+	}
+	for _, s := range steps {
+		st.Update(testParams, s.current, nil, s.now)
+		if st.State != s.state || st.Since != s.since || st.Time != s.now || st.Current != s.current {
+			t.Errorf("t=%d I=%v: state %d since %d, want %d since %d", s.now, s.current, st.State, st.Since, s.state, s.since)
+		}
+	}
+}
+
+// 放电容量按相邻采集的平均放电电流累计，间隔超过 MaxIntegrateGap 的一段不计
+func TestUpdateDischargeAh(t *testing.T) {
+	st := &State{}
+	cells := func(u ...float64) []Cell {
+		out := make([]Cell, len(u))
+		for i, v := range u {
+			out[i] = Cell{No: i + 1, Value: v}
+		}
+		return out
+	}
+	steps := []struct {
+		now     int64
+		current float64
+		cellU   []Cell
+	}{
+		{1000, 0, nil},
+		{1060, -10, cells(2.0, 1.95)},  // 进入放电，从本次采集开始累计
+		{1120, -20, cells(1.9, 1.97)},  // 15 A × 60 s
+		{1720, -20, cells(1.92, 1.93)}, // 间隔 600 s 不计
+		{1780, 0, nil},                 // 离开放电：10 A × 60 s
+	}
+	var done *Discharge
+	for i, s := range steps {
+		done = st.Update(testParams, s.current, s.cellU, s.now)
+		if i < len(steps)-1 && done != nil {
+			t.Fatalf("t=%d: discharge ended early", s.now)
+		}
+	}
+	if done == nil {
+		t.Fatal("no discharge record after current returned to idle")
+	}
+	want := math.Round((15*60+10*60)/3600.0*1000) / 1000
+	if !near(done.Ah, want) || done.Start != 1060 || done.End != 1780 {
+		t.Errorf("discharge = %+v, want %.3f Ah from 1060 to 1780", done, want)
+	}
+	if done.MinCell != 1 || done.MinU != 1.9 {
+		t.Errorf("min cell = %d %.3f V, want 1 1.900 V", done.MinCell, done.MinU)
+	}
+	if st.Active != nil || st.Last == nil || st.Last.Ah != done.Ah {
+		t.Errorf("state after discharge = active %v last %v", st.Active, st.Last)
+	}
+}
+
+func TestCountSOC(t *testing.T) {
+	st := &State{}
+	st.Update(testParams, 0, nil, 1000)
+	if *st.SOC != 100 || st.SOCConf != ConfNone {
+		t.Fatalf("initial SOC = %v conf %d, want 100 uncalibrated", *st.SOC, st.SOCConf)
+	}
+	// 0→20 A 平均 10 A × 60 s + 20 A × 180 s = 4200 As，容量 100 Ah
+	st.Update(testParams, -20, nil, 1060)
+	st.Update(testParams, -20, nil, 1240)
+	if !near(*st.SOC, 100-4200/3600.0) {
+		t.Errorf("SOC after discharge = %v", *st.SOC)
+	}
+
+	// 静置 rest_min 后按平均单体电压校准：2.055 V 为 50%
+	u := []Cell{{No: 1, Value: 2.05}, {No: 2, Value: 2.06}}
+	now := int64(1300)
+	st.Update(testParams, 0, u, now)
+	now += 120 * 60
+	st.Update(testParams, 0, u, now)
+	if !near(*st.SOC, 50) || st.SOCConf != ConfFull {
+		t.Errorf("SOC after rest = %v conf %d, want 50 calibrated", *st.SOC, st.SOCConf)
+	}
+
+	// 离开静置后为推算；浮充 float_min 后校准为 100%
+	now += 60
+	st.Update(testParams, 2, nil, now)
+	if st.SOCConf != ConfPartial {
+		t.Errorf("conf after leaving rest = %d, want partial", st.SOCConf)
+	}
+	st.Update(testParams, 2, nil, now+60*60)
+	if *st.SOC != 100 || st.SOCConf != ConfFull {
+		t.Errorf("SOC after float = %v conf %d, want 100 calibrated", *st.SOC, st.SOCConf)
+	}
+}
+
+func TestEstimateSOH(t *testing.T) {
+	s, err := ParseSOH("0.2", "50", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := &State{}
+	if st.EstimateSOH(s, 1, nil, 100) || st.SOH != nil {
+		t.Fatal("EstimateSOH without resistance")
+	}
+	if !st.EstimateSOH(s, 1, []Cell{{1, 0.28}, {2, 0.32}}, 100) || !near(*st.SOH, 80) || st.SOHTime != 100 {
+		t.Errorf("SOH = %v at %d, want 80 at 100", *st.SOH, st.SOHTime)
+	}
+	// 本次未读到内阻：保留上次结果与时间，由驱动按 SOHTime 给出时效
+	if st.EstimateSOH(s, 1, nil, 200) || !near(*st.SOH, 80) || st.SOHTime != 100 {
+		t.Errorf("SOH after missed poll = %v at %d", *st.SOH, st.SOHTime)
+	}
+}
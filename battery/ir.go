@@ -0,0 +1,97 @@
+package battery
+
+// 单体内阻趋势
+//
+// 每个单体保存内阻基准与滚动平均：基准取建立后第一次读到的内阻；前 Window
+// 次采集为算术平均，之后为同窗口的指数平均。滚动平均高于自身基准 Growth%
+// 以上告警“内阻增长”，高于本组各单体滚动平均的中位数 MedianDev% 以上告警
+// “偏离中位数”。只有本次读到正内阻的单体参与中位数并给出变化率与告警。
+// 删除单体的记录（Reset）后下一次读到时重新建立基准。
+
+// 内阻告警位，IRStatus.Alarm 取值为各位之和
+const (
+	IRAlarmGrowth = 1
+	IRAlarmMedian = 2
+)
+
+// IRParams 内阻趋势参数
+type IRParams struct {
+	Window    int     // 滚动平均的采集次数
+	Growth    float64 // 高于自身基准多少 % 告警
+	MedianDev float64 // 高于本组中位数多少 % 告警
+}
+
+// IRCell 一个单体跨轮询保存的内阻趋势
+type IRCell struct {
+	Base float64 `json:"base"` // 基准
+	Avg  float64 `json:"avg"`  // 滚动平均
+	N    int     `json:"n"`    // 参与平均的采集次数，不超过 Window
+}
+
+// IRTrend 一组各单体的内阻趋势，键为内阻字段名（不含组前缀，如 IR07）
+type IRTrend map[string]*IRCell
+
+// IRStatus 一个单体本次的内阻趋势；Reason 非空时本次没有变化率与告警
+type IRStatus struct {
+	Rate   float64 // 滚动平均相对基准的变化率（%）
+	Alarm  int     // 告警位
+	Reason string
+}
+
+func irKey(no int) string {
+	return "IR" + CellNo(no)
+}
+
+// Reset 删除第 no 号单体的基准与滚动平均
+func (t IRTrend) Reset(no int) {
+	delete(t, irKey(no))
+}
+
+// Track 用本次读到的单体内阻 values 更新滚动平均（首次读到时建立基准），
+// 返回 nos 各单体的状态与告警单体数
+func (t IRTrend) Track(p IRParams, values []Cell, nos []int) ([]IRStatus, int) {
+	fresh := map[int]bool{}
+	var avgs []float64
+	for _, c := range values {
+		if c.Value <= 0 {
+			continue
+		}
+		fresh[c.No] = true
+		ic := t[irKey(c.No)]
+		if ic == nil {
+			ic = &IRCell{Base: c.Value}
+			t[irKey(c.No)] = ic
+		}
+		if ic.N < p.Window || ic.N == 0 {
+			ic.N++
+		}
+		ic.Avg += (c.Value - ic.Avg) / float64(ic.N)
+		avgs = append(avgs, ic.Avg)
+	}
+	mid := median(avgs)
+
+	out := make([]IRStatus, len(nos))
+	count := 0
+	for i, no := range nos {
+		ic := t[irKey(no)]
+		switch {
+		case ic == nil:
+			out[i].Reason = "no resistance read"
+			continue
+		case !fresh[no]:
+			out[i].Reason = "resistance not read this poll"
+			continue
+		}
+		if ic.Avg > ic.Base*(1+p.Growth/100) {
+			out[i].Alarm |= IRAlarmGrowth
+		}
+		if mid > 0 && ic.Avg > mid*(1+p.MedianDev/100) {
+			out[i].Alarm |= IRAlarmMedian
+		}
+		if out[i].Alarm != 0 {
+			count++
+		}
+		out[i].Rate = (ic.Avg/ic.Base - 1) * 100
+	}
+	return out, count
+}
@@ -0,0 +1,66 @@
+package battery
+
+import "testing"
+
+func irCells(values ...float64) []Cell {
+	out := make([]Cell, 0, len(values))
+	for i, v := range values {
+		if v != 0 {
+			out = append(out, Cell{No: i + 1, Value: v})
+		}
+	}
+	return out
+}
+
+func TestIRTrackAlarms(t *testing.T) {
+	p := IRParams{Window: 2, Growth: 25, MedianDev: 30}
+	nos := []int{1, 2, 3, 4}
+	trend := IRTrend{}
+
+	// 首次读到建立基准；4 号未读到
+	status, count := trend.Track(p, irCells(0.20, 0.21, 0.30, 0), nos)
+	if count != 1 || status[2].Alarm != IRAlarmMedian || status[0].Alarm != 0 || status[0].Rate != 0 {
+		t.Errorf("first poll = %+v, count %d; want only cell 3 above median", status, count)
+	}
+	if status[3].Reason != "no resistance read" {
+		t.Errorf("cell 4 reason = %q", status[3].Reason)
+	}
+
+	tests := []struct {
+		values []float64
+		rate   float64 // 1 号单体
+		alarm  int     // 1 号单体
+		count  int
+	}{
+		// 窗口 2：(0.20+0.30)/2 = 0.25，增长 25% 未超过
+		{[]float64{0.30, 0.21, 0.30, 0.20}, 25, 0, 1},
+		// 指数平均：0.25 + (0.45-0.25)/2 = 0.35，增长 75% 且高于中位数 0.255 的 1.3 倍
+		{[]float64{0.45, 0.21, 0.30, 0.20}, 75, IRAlarmGrowth | IRAlarmMedian, 1},
+	}
+	for i, tt := range tests {
+		status, count := trend.Track(p, irCells(tt.values...), nos)
+		if !near(status[0].Rate, tt.rate) || status[0].Alarm != tt.alarm || count != tt.count {
+			t.Errorf("poll %d: cell 1 = %+v, count %d; want rate %v alarm %d count %d", i+2, status[0], count, tt.rate, tt.alarm, tt.count)
+		}
+	}
+}
+
+func TestIRTrackMissedPollAndReset(t *testing.T) {
+	p := IRParams{Window: 20, Growth: 25, MedianDev: 30}
+	nos := []int{1, 2}
+	trend := IRTrend{}
+	trend.Track(p, irCells(0.20, 0.20), nos)
+
+	// 本次未读到的单体保留记录，但不给出变化率与告警
+	status, _ := trend.Track(p, irCells(0, 0.22), nos)
+	if status[0].Reason != "resistance not read this poll" || trend["IR01"] == nil {
+		t.Errorf("cell 1 = %+v, record %v", status[0], trend["IR01"])
+	}
+
+	// 重建基准后按下一次读数重新建立
+	trend.Reset(2)
+	status, _ = trend.Track(p, irCells(0.20, 0.40), nos)
+	if c := trend["IR02"]; c == nil || c.Base != 0.40 || c.N != 1 || status[1].Rate != 0 {
+		t.Errorf("cell 2 after reset = %+v, status %+v", c, status[1])
+	}
+}
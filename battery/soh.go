@@ -0,0 +1,88 @@
+package battery
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SOH 按平均单体内阻相对投运基准 ir_baseline 的增长折算：增长 0 为 100%，
+// 增长 ir_eol% 为 80%（寿命终止），线性外推并限制在 0~100%。
+
+// DefaultEOL 缺省寿命终止内阻增长（%）
+const DefaultEOL = 50.0
+
+// EOLSOH 寿命终止时的 SOH（%）
+const EOLSOH = 80.0
+
+// SOH 估算参数
+type SOH struct {
+	Baseline []float64 // 各组投运时平均单体内阻，未配置时为空
+	EOL      float64   // 寿命终止内阻增长（%），寿命终止内阻 = 基准 × (1 + EOL/100)
+}
+
+// ParseSOH 解析 ir_baseline 与 ir_eol，n 为电池组数。ir_baseline 为数值时
+// 各组相同，为数组时须每组一个；基准须大于 0，ir_eol 须大于 0，保证寿命终止
+// 内阻高于基准。ir_baseline 为空时不估算 SOH。
+func ParseSOH(baseline, eol string, n int) (SOH, error) {
+	s := SOH{EOL: DefaultEOL}
+	if v := strings.TrimSpace(eol); v != "" {
+		pct, err := strconv.ParseFloat(v, 64)
+		if err != nil || !(pct > 0) || math.IsInf(pct, 0) {
+			return SOH{}, errors.New("ir_eol must be a number greater than 0")
+		}
+		s.EOL = pct
+	}
+
+	raw := strings.TrimSpace(baseline)
+	if raw == "" {
+		return s, nil
+	}
+	if v, err := strconv.ParseFloat(raw, 64); err == nil {
+		s.Baseline = make([]float64, n)
+		for i := range s.Baseline {
+			s.Baseline[i] = v
+		}
+	} else if json.Unmarshal([]byte(raw), &s.Baseline) != nil {
+		return SOH{}, errors.New("ir_baseline: want a number or a JSON array of numbers")
+	} else if len(s.Baseline) != n {
+		return SOH{}, errors.New("ir_baseline: want " + strconv.Itoa(n) + " values")
+	}
+	for i, v := range s.Baseline {
+		if !(v > 0) || math.IsInf(v, 0) {
+			return SOH{}, errors.New("ir_baseline[" + strconv.Itoa(i) + "] must be greater than 0")
+		}
+	}
+	return s, nil
+}
+
+// Configured 是否配置了内阻基准
+func (s SOH) Configured() bool {
+	return len(s.Baseline) > 0
+}
+
+// Estimate 第 no 组（从 1 开始）平均单体内阻为 ir 时的 SOH，线性外推并限制在
+// 0~100%；未配置基准时返回 false
+func (s SOH) Estimate(no int, ir float64) (float64, bool) {
+	if no < 1 || no > len(s.Baseline) {
+		return 0, false
+	}
+	growth := math.Max(0, (ir/s.Baseline[no-1]-1)*100)
+	return math.Max(0, math.Min(100, 100-growth/s.EOL*(100-EOLSOH))), true
+}
+
+// EstimateSOH 按本次读到的单体内阻 ir 估算第 no 组的 SOH 并记下估算时间；
+// 未配置基准或本次未读到内阻时不改变上次结果并返回 false
+func (st *State) EstimateSOH(s SOH, no int, ir []Cell, now int64) bool {
+	if len(ir) == 0 {
+		return false
+	}
+	v, ok := s.Estimate(no, StatsOf(ir).Mean)
+	if !ok {
+		return false
+	}
+	st.SOH, st.SOHTime = &v, now
+	return true
+}
@@ -0,0 +1,60 @@
+package battery
+
+import "testing"
+
+func TestParseSOHRejects(t *testing.T) {
+	for _, c := range []struct{ baseline, eol, want string }{
+		{"0.2", "0", "ir_eol must be a number greater than 0"},
+		{"0.2", "-10", "ir_eol must be a number greater than 0"},
+		{"0.2", "x", "ir_eol must be a number greater than 0"},
+		{"0", "", "ir_baseline[0] must be greater than 0"},
+		{"[0.2, -0.1]", "", "ir_baseline[1] must be greater than 0"},
+		{"[0.2]", "", "ir_baseline: want 2 values"},
+		{"abc", "", "ir_baseline: want a number or a JSON array of numbers"},
+	} {
+		_, err := ParseSOH(c.baseline, c.eol, 2)
+		if err == nil || err.Error() != c.want {
+			t.Errorf("ParseSOH(%q, %q) err = %v, want %q", c.baseline, c.eol, err, c.want)
+		}
+	}
+}
+
+func TestParseSOHDefaults(t *testing.T) {
+	s, err := ParseSOH("", "", 2)
+	if err != nil || s.Configured() || s.EOL != DefaultEOL {
+		t.Errorf("empty config = %+v, %v", s, err)
+	}
+	if _, ok := s.Estimate(1, 0.3); ok {
+		t.Error("Estimate without baseline")
+	}
+
+	s, err = ParseSOH(" 0.2 ", "", 2)
+	if err != nil || len(s.Baseline) != 2 || s.Baseline[1] != 0.2 {
+		t.Errorf("shared baseline = %+v, %v", s, err)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	s, err := ParseSOH("[0.2, 0.4]", "50", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		no       int
+		ir, want float64
+	}{
+		{1, 0.2, 100},
+		{1, 0.18, 100}, // 低于基准不高于 100%
+		{1, 0.3, 80},   // 增长 50%：寿命终止
+		{2, 0.5, 90},
+		{1, 1.2, 0}, // 线性外推后限制在 0
+	} {
+		got, ok := s.Estimate(c.no, c.ir)
+		if !ok || got < c.want-1e-9 || got > c.want+1e-9 {
+			t.Errorf("Estimate(%d, %v) = %v, want %v", c.no, c.ir, got, c.want)
+		}
+	}
+	if _, ok := s.Estimate(3, 0.2); ok {
+		t.Error("Estimate for string 3 of 2")
+	}
+}
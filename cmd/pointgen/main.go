@@ -0,0 +1,208 @@
+// Command pointgen 根据驱动目录下的 points.xlsx 生成点表源码。
+//
+// 驱动源码中用一对标记圈出生成区：
+//
+//	// 【自动生成】pointgen -max 50 -gap 2
+//	var pointConfig = []point.Point{...}
+//	var readBlocks = []modbus.Block{...}
+//	// 【自动生成】结束
+//
+// pointgen 重写标记之间的 pointConfig 与 readBlocks；开始标记后的选项
+// 按驱动单独配置（-max 单个请求最多读取的寄存器数，默认 125；-gap 合并
+// 时允许跨过的未定义寄存器数，默认 0）。
+//
+// 用法:
+//
+//	go run ./cmd/pointgen [-check] [目录 ...]
+//
+// 不指定目录时处理当前目录下所有含 points.xlsx 的目录。-check 只比较
+// 不写文件，点表与代码不一致时以非 0 退出，可用于 CI。
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gonglijing/xunjiFsu/drvs/internal/pointtable"
+)
+
+const sheetName = "points.xlsx"
+
+func main() {
+	check := flag.Bool("check", false, "只检查点表与代码是否一致，不写文件")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: pointgen [-check] [dir ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dirs := flag.Args()
+	if len(dirs) == 0 {
+		var err error
+		if dirs, err = findSheetDirs("."); err != nil {
+			fmt.Fprintln(os.Stderr, "pointgen:", err)
+			os.Exit(2)
+		}
+	}
+
+	failed := false
+	for _, dir := range dirs {
+		if err := processDir(dir, *check); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", dir, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func findSheetDirs(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && strings.HasPrefix(d.Name(), ".") && path != root {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && d.Name() == sheetName {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	sort.Strings(dirs)
+	return dirs, err
+}
+
+// processDir 生成或检查一个驱动目录
+func processDir(dir string, check bool) error {
+	file, src, r, err := findDriver(dir)
+	if err != nil {
+		return err
+	}
+	if file == "" {
+		fmt.Printf("skip %s: no %q region\n", dir, pointtable.BeginMarker)
+		return nil
+	}
+
+	opts, err := parseOptions(r.Options)
+	if err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	rows, err := pointtable.ReadSheet(filepath.Join(dir, sheetName))
+	if err != nil {
+		return err
+	}
+	entries, err := pointtable.Parse(rows)
+	if err != nil {
+		return fmt.Errorf("%s: %v", sheetName, err)
+	}
+	blocks, err := pointtable.Plan(entries, opts.maxCount, opts.maxGap)
+	if err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	out, err := pointtable.Rewrite(src, r, pointtable.Render(entries, blocks))
+	if err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+
+	if bytes.Equal(out, src) {
+		if !check {
+			fmt.Printf("ok %s\n", file)
+		}
+		return nil
+	}
+	if check {
+		return fmt.Errorf("%s is out of date with %s:\n%s", filepath.Base(file), sheetName, lineDiff(src, out))
+	}
+	if err := os.WriteFile(file, out, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("updated %s (%d points)\n", file, len(entries))
+	return nil
+}
+
+// findDriver 在目录中找到含生成区的 Go 源文件
+func findDriver(dir string) (string, []byte, pointtable.Region, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, pointtable.Region{}, err
+	}
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(f)
+		if err != nil {
+			return "", nil, pointtable.Region{}, err
+		}
+		r, ok, err := pointtable.FindRegion(src)
+		if err != nil {
+			return "", nil, pointtable.Region{}, fmt.Errorf("%s: %v", f, err)
+		}
+		if ok {
+			return f, src, r, nil
+		}
+	}
+	return "", nil, pointtable.Region{}, nil
+}
+
+type options struct {
+	maxCount uint16
+	maxGap   uint16
+}
+
+func parseOptions(s string) (options, error) {
+	opts := options{}
+	fset := flag.NewFlagSet(pointtable.BeginMarker, flag.ContinueOnError)
+	fset.SetOutput(new(bytes.Buffer))
+	maxCount := fset.Uint("max", 125, "")
+	maxGap := fset.Uint("gap", 0, "")
+	if err := fset.Parse(strings.Fields(s)); err != nil {
+		return opts, fmt.Errorf("marker options %q: %v", s, err)
+	}
+	if *maxCount == 0 || *maxCount > 125 {
+		return opts, fmt.Errorf("marker options %q: -max must be 1~125", s)
+	}
+	if *maxGap >= *maxCount {
+		return opts, fmt.Errorf("marker options %q: -gap must be less than -max", s)
+	}
+	opts.maxCount = uint16(*maxCount)
+	opts.maxGap = uint16(*maxGap)
+	return opts, nil
+}
+
+// lineDiff 列出两份源码中各自独有的行，足以定位不一致的测点
+func lineDiff(old, new []byte) string {
+	count := map[string]int{}
+	for _, l := range strings.Split(string(old), "\n") {
+		count[l]++
+	}
+	var added []string
+	for _, l := range strings.Split(string(new), "\n") {
+		if count[l] > 0 {
+			count[l]--
+			continue
+		}
+		added = append(added, "+ "+strings.TrimSpace(l))
+	}
+	var removed []string
+	for _, l := range strings.Split(string(old), "\n") {
+		if count[l] > 0 {
+			count[l]--
+			removed = append(removed, "- "+strings.TrimSpace(l))
+		}
+	}
+	lines := append(removed, added...)
+	if len(lines) > 40 {
+		lines = append(lines[:40], fmt.Sprintf("... %d more", len(lines)-40))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,300 @@
+// Command tslimport 从旧平台（pandax）导出的 SQLite 数据库生成驱动骨架。
+//
+// 读取 devices 与 device_tsls 两张表：
+//   - devices.define 中的串口参数（波特率、数据位、校验、停止位）与协议
+//   - device_tsls 中该设备的全部测点（属性名、标识、功能码、地址、数量、表达式…）
+//
+// 在输出目录生成 points.xlsx、<name>.go、Makefile 与 README.md，驱动源码按
+// 【固定不变】/【用户修改】分区，点表与读取分组由 pointgen 生成区填充。
+//
+// 用法:
+//
+//	go run ./cmd/tslimport -db pandax230.db -list
+//	go run ./cmd/tslimport -db pandax230.db -did t8AyvifdGo -name qingniao_fire -out 站点/青鸟消防
+//
+// 需要本机安装 sqlite3 命令行（3.33+，支持 -json）；-db 也可以是目录，内含
+// sqlite3 -json 导出的 devices.json 与 device_tsls.json，此时不需要 sqlite3。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gonglijing/xunjiFsu/drvs/internal/pointtable"
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+)
+
+func main() {
+	var (
+		db      = flag.String("db", "", "SQLite 数据库文件（pandax230.db）")
+		did     = flag.String("did", "", "设备 ID（devices.id）")
+		name    = flag.String("name", "", "驱动文件名（不含 .go），如 qingniao_fire")
+		out     = flag.String("out", "", "输出目录，默认 ./<name>")
+		title   = flag.String("title", "", "驱动标题，默认取 devices.name")
+		proto   = flag.String("proto", "", "rtu | tcp，默认按 devices.define 推断")
+		maxRegs = flag.Uint("max", 125, "单次读取最多寄存器数（写入 pointgen 标记）")
+		maxGap  = flag.Uint("gap", 0, "合并读取时允许跨过的未定义寄存器数（写入 pointgen 标记）")
+		list    = flag.Bool("list", false, "列出数据库中的设备")
+		force   = flag.Bool("force", false, "覆盖已存在的文件")
+		sqlite3 = flag.String("sqlite3", "sqlite3", "sqlite3 命令路径")
+	)
+	flag.Parse()
+
+	if *db == "" {
+		fail("missing -db")
+	}
+	if *list {
+		devices, err := loadTable(*sqlite3, *db, "devices")
+		if err != nil {
+			fail("%v", err)
+		}
+		listDevices(devices)
+		return
+	}
+	if *did == "" || *name == "" {
+		fail("missing -did or -name")
+	}
+	if !regexp.MustCompile(`^[a-z][a-z0-9_]*$`).MatchString(*name) {
+		fail("-name %q: want lower_snake_case", *name)
+	}
+	if *maxRegs == 0 || *maxRegs > 125 {
+		fail("-max must be 1~125")
+	}
+	if *maxGap >= *maxRegs {
+		fail("-gap must be less than -max")
+	}
+
+	err := generate(options{
+		DB:      *db,
+		Did:     *did,
+		Name:    *name,
+		Out:     *out,
+		Title:   *title,
+		Proto:   *proto,
+		MaxRegs: *maxRegs,
+		MaxGap:  *maxGap,
+		Force:   *force,
+		Sqlite3: *sqlite3,
+	})
+	if err != nil {
+		fail("%v", err)
+	}
+}
+
+// options 已校验的命令行参数
+type options struct {
+	DB, Did, Name, Out, Title, Proto string
+	MaxRegs, MaxGap                  uint
+	Force                            bool
+	Sqlite3                          string
+}
+
+// generate 读取设备与测点，在输出目录写出驱动骨架
+func generate(o options) error {
+	devices, err := loadTable(o.Sqlite3, o.DB, "devices")
+	if err != nil {
+		return err
+	}
+	dev, ok := findDevice(devices, o.Did)
+	if !ok {
+		return fmt.Errorf("device %s not found in devices", o.Did)
+	}
+	tsls, err := loadTable(o.Sqlite3, o.DB, "device_tsls")
+	if err != nil {
+		return err
+	}
+	rows := pointRows(tsls, o.Did)
+	if len(rows) <= 1 {
+		return fmt.Errorf("device %s has no device_tsls rows", o.Did)
+	}
+	entries, err := pointtable.Parse(rows)
+	if err != nil {
+		return fmt.Errorf("device_tsls: %v", err)
+	}
+	blocks, err := pointtable.Plan(entries, uint16(o.MaxRegs), uint16(o.MaxGap))
+	if err != nil {
+		return err
+	}
+
+	info := skeleton{
+		Name:     o.Name,
+		Title:    o.Title,
+		Did:      o.Did,
+		DB:       o.DB,
+		Serial:   serialParams(dev),
+		TCP:      isTCP(dev, o.Proto),
+		Points:   len(entries),
+		MaxRegs:  o.MaxRegs,
+		MaxGap:   o.MaxGap,
+		Ranges:   describeBlocks(blocks),
+		Examples: entries,
+	}
+	if info.Title == "" {
+		info.Title = dev.column("name")
+	}
+	if info.Title == "" {
+		info.Title = o.Name
+	}
+
+	dir := o.Out
+	if dir == "" {
+		dir = o.Name
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return writeDriver(dir, info, rows, entries, o.Force)
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "tslimport: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+func listDevices(devices []map[string]interface{}) {
+	for _, row := range devices {
+		r := newRecord(row)
+		fmt.Printf("%s\t%s\t%s\n", r.column("id", "did"), r.column("name"), r.define("protocol", "protocol_name", "protocol_type"))
+	}
+}
+
+func findDevice(devices []map[string]interface{}, did string) (record, bool) {
+	for _, row := range devices {
+		r := newRecord(row)
+		if r.column("id", "did", "device_id") == did {
+			return r, true
+		}
+	}
+	return record{}, false
+}
+
+// pointRows 把设备的 device_tsls 行转换为 points.xlsx 的行，按功能码、地址排序
+func pointRows(tsls []map[string]interface{}, did string) [][]string {
+	var recs []record
+	for _, row := range tsls {
+		r := newRecord(row)
+		if r.column("did", "device_id", "deviceid", "device") == did {
+			recs = append(recs, r)
+		}
+	}
+
+	rows := [][]string{pointtable.Columns}
+	for _, r := range recs {
+		rows = append(rows, []string{
+			r.column("name", "label", "title"),
+			r.column("key", "identifier", "field", "field_name"),
+			r.define("func_code", "function_code", "register_type", "funcCode"),
+			r.define("address", "register_address", "start_address", "addr"),
+			r.define("quantity", "length", "register_count", "count", "size"),
+			r.define("precision", "decimals", "decimal"),
+			r.define("data_type", "value_type", "type"),
+			r.define("rw", "mode", "access_mode", "access"),
+			r.define("expression", "expr", "formula"),
+			r.define("unit"),
+			"",
+		})
+	}
+	body := rows[1:]
+	sort.SliceStable(body, func(i, j int) bool {
+		if body[i][2] != body[j][2] {
+			return body[i][2] < body[j][2]
+		}
+		ai, _ := strconv.Atoi(body[i][3])
+		aj, _ := strconv.Atoi(body[j][3])
+		return ai < aj
+	})
+	return rows
+}
+
+// serialParams 以 "9600,8,N,1" 形式返回 devices.define 中的串口参数
+func serialParams(dev record) string {
+	baud := dev.define("baud_rate", "baud", "baudrate")
+	if baud == "" {
+		return ""
+	}
+	dataBits := dev.define("data_bits", "databits")
+	if dataBits == "" {
+		dataBits = "8"
+	}
+	stopBits := dev.define("stop_bits", "stopbits")
+	if stopBits == "" {
+		stopBits = "1"
+	}
+	parity := "N"
+	switch p := strings.ToUpper(dev.define("parity", "check_bit")); {
+	case strings.HasPrefix(p, "E"), p == "偶校验":
+		parity = "E"
+	case strings.HasPrefix(p, "O"), p == "奇校验":
+		parity = "O"
+	}
+	return strings.Join([]string{baud, dataBits, parity, stopBits}, ",")
+}
+
+func isTCP(dev record, proto string) bool {
+	if proto != "" {
+		return strings.EqualFold(proto, "tcp")
+	}
+	p := strings.ToLower(dev.define("protocol", "protocol_name", "protocol_type"))
+	return strings.Contains(p, "tcp")
+}
+
+func describeBlocks(blocks []modbus.Block) string {
+	var parts []string
+	for _, b := range blocks {
+		parts = append(parts, fmt.Sprintf("%d+%d", b.Start, b.Count))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeDriver 写出点表、驱动源码、Makefile 与 README，并填充 pointgen 生成区
+func writeDriver(dir string, info skeleton, rows [][]string, entries []pointtable.Entry, force bool) error {
+	goFile := filepath.Join(dir, info.Name+".go")
+	files := map[string]string{
+		goFile:                            renderTemplate(driverTemplate, info),
+		filepath.Join(dir, "Makefile"):    renderTemplate(makefileTemplate, info),
+		filepath.Join(dir, "README.md"):   renderTemplate(readmeTemplate, info),
+		filepath.Join(dir, "points.xlsx"): "",
+	}
+	if !force {
+		for f := range files {
+			if _, err := os.Stat(f); err == nil {
+				return fmt.Errorf("%s exists, use -force to overwrite", f)
+			}
+		}
+	}
+
+	if err := pointtable.WriteSheet(filepath.Join(dir, "points.xlsx"), rows); err != nil {
+		return err
+	}
+	src := []byte(files[goFile])
+	r, _, err := pointtable.FindRegion(src)
+	if err != nil {
+		return err
+	}
+	blocks, err := pointtable.Plan(entries, uint16(info.MaxRegs), uint16(info.MaxGap))
+	if err != nil {
+		return err
+	}
+	src, err = pointtable.Rewrite(src, r, pointtable.Render(entries, blocks))
+	if err != nil {
+		return err
+	}
+	files[goFile] = string(src)
+
+	for f, data := range files {
+		if strings.HasSuffix(f, ".xlsx") {
+			continue
+		}
+		if err := os.WriteFile(f, []byte(data), 0o644); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("generated %s (%d points, blocks %s)\n", dir, info.Points, info.Ranges)
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gonglijing/xunjiFsu/drvs/internal/pointtable"
+)
+
+var update = flag.Bool("update", false, "按本次输出重写 testdata/golden")
+
+// testdata/export 为 sqlite3 -json 导出的 devices / device_tsls，
+// testdata/golden 为 dev01 生成的驱动骨架
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	o := options{DB: "testdata/export", Did: "dev01", Name: "demo_meter", Out: dir, MaxRegs: 125}
+	if err := generate(o); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := pointtable.ReadSheet(filepath.Join(dir, "points.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sheet strings.Builder
+	for _, row := range rows {
+		sheet.WriteString(strings.Join(row, "\t") + "\n")
+	}
+	outputs := map[string]string{"points.tsv": sheet.String()}
+	for _, name := range []string{"demo_meter.go", "Makefile", "README.md"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs[name] = string(data)
+	}
+	if src, err := format.Source([]byte(outputs["demo_meter.go"])); err != nil || string(src) != outputs["demo_meter.go"] {
+		t.Errorf("demo_meter.go is not gofmt-formatted: %v", err)
+	}
+
+	for name, got := range outputs {
+		golden := filepath.Join("testdata", "golden", name+".golden")
+		if *update {
+			if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != string(want) {
+			t.Errorf("%s differs from %s:\n%s", name, golden, got)
+		}
+	}
+
+	// 已存在的文件不加 -force 不覆盖
+	if err := generate(o); err == nil || !strings.Contains(err.Error(), "use -force to overwrite") {
+		t.Errorf("second run err = %v", err)
+	}
+	o.Force = true
+	if err := generate(o); err != nil {
+		t.Errorf("-force: %v", err)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	o := options{DB: "testdata/export", Name: "demo", Out: t.TempDir(), MaxRegs: 125}
+	for did, want := range map[string]string{
+		"dev09": "device dev09 not found in devices",
+		"dev03": "device dev03 has no device_tsls rows",
+	} {
+		o.Did = did
+		if err := generate(o); err == nil || err.Error() != want {
+			t.Errorf("did %s: err = %v, want %q", did, err, want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 旧平台导出的 SQLite 通过 sqlite3 命令行以 JSON 读取，不引入 cgo 依赖。
+
+// loadTable 读取整张表：db 为目录时读取其中的 <table>.json（sqlite3 -json 的
+// 导出结果），否则通过 sqlite3 查询
+func loadTable(sqlite3, db, table string) ([]map[string]interface{}, error) {
+	if fi, err := os.Stat(db); err == nil && fi.IsDir() {
+		data, err := os.ReadFile(filepath.Join(db, table+".json"))
+		if err != nil {
+			return nil, err
+		}
+		return decodeRows(data, table)
+	}
+	return queryTable(sqlite3, db, table)
+}
+
+// queryTable 读取整张表，每行以列名为键
+func queryTable(sqlite3, db, table string) ([]map[string]interface{}, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(sqlite3, "-readonly", "-json", db, "SELECT * FROM "+table)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("sqlite3 %s: %v: %s", table, err, strings.TrimSpace(stderr.String()))
+	}
+	return decodeRows(stdout.Bytes(), table)
+}
+
+// decodeRows 解析 sqlite3 -json 的输出，空表时 sqlite3 不输出任何内容
+func decodeRows(data []byte, table string) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return rows, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&rows); err != nil {
+		return nil, fmt.Errorf("sqlite3 %s: %v", table, err)
+	}
+	return rows, nil
+}
+
+// record 一行数据：列值与列中 JSON（如 define）展开后的叶子值，键统一归一化
+type record struct {
+	columns map[string]string
+	nested  map[string]string
+}
+
+func newRecord(row map[string]interface{}) record {
+	r := record{columns: map[string]string{}, nested: map[string]string{}}
+	for k, v := range row {
+		s := scalar(v)
+		r.columns[normKey(k)] = s
+		if t := strings.TrimSpace(s); strings.HasPrefix(t, "{") {
+			var obj map[string]interface{}
+			dec := json.NewDecoder(strings.NewReader(t))
+			dec.UseNumber()
+			if dec.Decode(&obj) == nil {
+				flatten(obj, r.nested)
+			}
+		}
+	}
+	return r
+}
+
+// flatten 递归展开 JSON 对象，同名键保留先出现的浅层值
+func flatten(obj map[string]interface{}, out map[string]string) {
+	var deeper []map[string]interface{}
+	for k, v := range obj {
+		if m, ok := v.(map[string]interface{}); ok {
+			deeper = append(deeper, m)
+			continue
+		}
+		if _, ok := out[normKey(k)]; !ok {
+			out[normKey(k)] = scalar(v)
+		}
+	}
+	for _, m := range deeper {
+		flatten(m, out)
+	}
+}
+
+// column 优先取表列，再取 JSON 中的值
+func (r record) column(keys ...string) string {
+	return r.lookup(r.columns, r.nested, keys)
+}
+
+// define 优先取 JSON 中的值，再取表列
+func (r record) define(keys ...string) string {
+	return r.lookup(r.nested, r.columns, keys)
+}
+
+func (r record) lookup(first, second map[string]string, keys []string) string {
+	for _, m := range []map[string]string{first, second} {
+		for _, k := range keys {
+			if v := strings.TrimSpace(m[normKey(k)]); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+func normKey(k string) string {
+	k = strings.ToLower(k)
+	k = strings.ReplaceAll(k, "_", "")
+	return strings.ReplaceAll(k, "-", "")
+}
+
+func scalar(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case json.Number:
+		return x.String()
+	case bool:
+		return strconv.FormatBool(x)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
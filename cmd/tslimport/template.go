@@ -0,0 +1,246 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/gonglijing/xunjiFsu/drvs/internal/pointtable"
+)
+
+// skeleton 生成驱动骨架所需的信息
+type skeleton struct {
+	Name     string // 驱动文件名
+	Title    string // 驱动标题
+	Did      string // 设备 ID
+	DB       string // 来源数据库
+	Serial   string // 串口参数 9600,8,N,1，TCP 设备为空
+	TCP      bool
+	Points   int
+	MaxRegs  uint
+	MaxGap   uint
+	Ranges   string // 读取分组摘要
+	Examples []pointtable.Entry
+}
+
+func (s skeleton) Protocol() string {
+	if s.TCP {
+		return "Modbus TCP"
+	}
+	return "Modbus RTU"
+}
+
+func (s skeleton) ProtocolID() string {
+	if s.TCP {
+		return "modbus-tcp"
+	}
+	return "modbus-rtu"
+}
+
+func (s skeleton) Host() string {
+	if s.TCP {
+		return "tcp_transceive"
+	}
+	return "serial_transceive"
+}
+
+func (s skeleton) Client() string {
+	if s.TCP {
+		return "NewTCPClient"
+	}
+	return "NewRTUClient"
+}
+
+// MarkerOptions pointgen 标记选项，默认值省略
+func (s skeleton) MarkerOptions() string {
+	var opts string
+	if s.MaxRegs != 125 {
+		opts += " -max " + strconv.FormatUint(uint64(s.MaxRegs), 10)
+	}
+	if s.MaxGap != 0 {
+		opts += " -gap " + strconv.FormatUint(uint64(s.MaxGap), 10)
+	}
+	return opts
+}
+
+// Sample README 点表样例，最多 5 行
+func (s skeleton) Sample() []pointtable.Entry {
+	if len(s.Examples) > 5 {
+		return s.Examples[:5]
+	}
+	return s.Examples
+}
+
+// Words 驱动名转为 Makefile 注释中的空格分隔形式
+func (s skeleton) Words() string {
+	return strings.ReplaceAll(s.Name, "_", " ")
+}
+
+func renderTemplate(t *template.Template, s skeleton) string {
+	var sb strings.Builder
+	if err := t.Execute(&sb, s); err != nil {
+		fail("template %s: %v", t.Name(), err)
+	}
+	return sb.String()
+}
+
+var driverTemplate = template.Must(template.New("driver").Parse(`// =============================================================================
+// {{.Title}} - {{.Protocol}} 驱动
+// =============================================================================
+//
+// 协议来源:
+//   - 数据库: {{.DB}}
+//   - 设备: {{.Title}} (did={{.Did}}, protocol={{.ProtocolID}})
+//
+// 点表摘要:
+//   - 总点位: {{.Points}}
+//   - 读取分组: {{.Ranges}}
+//   - 点表由 points.xlsx 经 pointgen 生成
+{{- if .Serial}}
+//   - 串口参数(devices.define): {{.Serial}}
+{{- end}}
+//
+// Host 提供: {{.Host}}
+//
+// =============================================================================
+package main
+
+import (
+	"github.com/gonglijing/xunjiFsu/drvs/driver"
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+	"github.com/gonglijing/xunjiFsu/drvs/point"
+)
+
+// =============================================================================
+// 【固定不变】Host 函数声明
+// =============================================================================
+//
+//go:wasmimport extism:host/user {{.Host}}
+func {{.Host}}(wPtr uint64, wSize uint64, rPtr uint64, rCap uint64, timeoutMs uint64) uint64
+
+// =============================================================================
+// 【用户修改】驱动版本
+// =============================================================================
+const DriverVersion = "1.0.0"
+
+// =============================================================================
+// 【用户修改】点表配置
+// =============================================================================
+// 工程值 = 原始值 * Scale + Offset，Expr 保留点表中的原始表达式
+// 【自动生成】pointgen{{.MarkerOptions}}
+// 【自动生成】结束
+
+// =============================================================================
+// 【固定不变】驱动入口
+// =============================================================================
+//
+//go:wasmexport handle
+func handle() int32 {
+	defer driver.Recover()
+
+	cfg := driver.GetConfig()
+	client := driver.{{.Client}}({{.Host}}, cfg.Debug)
+	points, errs := point.ReadPoints(client, byte(cfg.DeviceAddress), readBlocks, pointConfig)
+
+	driver.OutputJSON(point.Result(points, errs))
+	return 0
+}
+
+// =============================================================================
+// 【固定不变】描述点表
+// =============================================================================
+//
+//go:wasmexport describe
+func describe() int32 {
+	driver.OutputJSON(map[string]interface{}{
+		"success": true,
+		"data":    point.Describe(DriverVersion, "{{.ProtocolID}}", pointConfig),
+	})
+	return 0
+}
+
+// =============================================================================
+// 【固定不变】驱动版本
+// =============================================================================
+//
+//go:wasmexport version
+func version() int32 {
+	driver.OutputVersion(DriverVersion)
+	return 0
+}
+
+func main() {}
+`))
+
+var makefileTemplate = template.Must(template.New("makefile").Parse(`# {{.Words}} Driver Makefile
+# For FSU (Field Site Unit) project
+
+.PHONY: all clean test
+
+# Compiler settings
+TINYGO ?= tinygo
+TARGET ?= wasip1
+BUILDMODE ?= c-shared
+OPT ?= z
+
+# Makefile location (works from any current directory)
+MAKEFILE_DIR := $(patsubst %/,%,$(dir $(abspath $(lastword $(MAKEFILE_LIST)))))
+
+# Available drivers in this directory
+DRIVERS := {{.Name}}
+
+all: $(addprefix $(MAKEFILE_DIR)/,$(addsuffix .wasm,$(DRIVERS)))
+
+$(MAKEFILE_DIR)/%.wasm: $(MAKEFILE_DIR)/%.go
+	cd $(MAKEFILE_DIR) && $(TINYGO) build -o $@ -target=$(TARGET) -buildmode=$(BUILDMODE) -opt=$(OPT) ./$(notdir $<)
+
+test:
+	@echo "Running {{.Words}} driver tests..."
+	@if [ -f "$(MAKEFILE_DIR)/{{.Name}}_test.go" ]; then cd $(MAKEFILE_DIR) && $(TINYGO) test -target=$(TARGET) ./... || true; else echo "No tests found"; fi
+
+clean:
+	rm -f $(addprefix $(MAKEFILE_DIR)/,$(addsuffix .wasm,$(DRIVERS)))
+`))
+
+var readmeTemplate = template.Must(template.New("readme").Parse(`# {{.Title}} {{.Protocol}} 驱动
+
+## 设备信息
+
+- 设备类型：{{.Title}}
+- 协议类型：{{.Protocol}}
+- 协议来源：` + "`{{.DB}}`" + `
+- 设备标识：` + "`did={{.Did}}`" + `，共 ` + "`{{.Points}}`" + ` 个测点
+- 驱动文件：` + "`{{.Name}}.go`" + `
+- 产物文件：` + "`{{.Name}}.wasm`" + `
+
+## 点表概览
+
+说明：点位由 ` + "`cmd/tslimport`" + ` 从数据库 ` + "`device_tsls`" + ` 表导入到 ` + "`points.xlsx`" + `，以下仅展示样例。
+
+| 属性名 | 属性标识 | 寄存器地址 | 寄存器数量 | 表达式 | 读写 |
+|---|---|---:|---:|---|---|
+{{- range .Sample}}
+| {{.Label}} | ` + "`{{.Field}}`" + ` | {{.Address}} | {{.Length}} | ` + "`{{.Expr}}`" + ` | {{.RW}} |
+{{- end}}
+
+## 读取策略
+
+- 读取分组：{{.Ranges}}
+- 单次请求寄存器数量不超过 ` + "`{{.MaxRegs}}`" + `
+{{- if .MaxGap}}
+- 间隔不超过 ` + "`{{.MaxGap}}`" + ` 个寄存器的测点合并读取
+{{- end}}
+
+## 编译
+
+` + "```bash" + `
+make {{.Name}}.wasm
+` + "```" + `
+
+## 网关配置建议
+
+- ` + "`device_address`" + `：设备从站地址（默认 ` + "`1`" + `）
+{{- if .Serial}}
+- 串口参数：以数据库 ` + "`devices.define`" + ` 为准（` + "`{{.Serial}}`" + `）
+{{- end}}
+`))
@@ -0,0 +1,68 @@
+// Package driver 提供驱动共用的 Extism 胶水代码：配置解析、JSON 输出、
+// 调试日志以及宿主收发函数到 modbus.Transport 的适配。
+package driver
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	pdk "github.com/extism/go-pdk"
+)
+
+// Config 网关传入的配置
+type Config struct {
+	DeviceAddress int    `json:"device_address"` // Modbus 从站地址
+	FuncName      string `json:"func_name"`      // "read" | "write"
+	FieldName     string `json:"field_name"`     // 可写字段名
+	Value         string `json:"value"`          // 写操作的值
+	Debug         bool   `json:"debug"`          // 调试模式
+
+	// Raw 原始 config 键值，供驱动读取自定义参数；
+	// 非字符串的值（数字、数组、对象）保留为 JSON 文本
+	Raw map[string]string `json:"-"`
+}
+
+// GetConfig 解析输入中的 config 信封，缺省 device_address=1, func_name=read
+func GetConfig() Config {
+	def := Config{DeviceAddress: 1, FuncName: "read", Raw: map[string]string{}}
+	var envelope struct {
+		Config map[string]json.RawMessage `json:"config"`
+	}
+	if err := pdk.InputJSON(&envelope); err != nil || envelope.Config == nil {
+		return def
+	}
+
+	cfg := def
+	for k, raw := range envelope.Config {
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			cfg.Raw[k] = s
+		} else if string(raw) != "null" {
+			cfg.Raw[k] = string(raw)
+		}
+	}
+	if v := strings.TrimSpace(cfg.Raw["device_address"]); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.DeviceAddress = n
+		}
+	}
+	if v := strings.TrimSpace(cfg.Raw["func_name"]); v != "" {
+		cfg.FuncName = v
+	}
+	if v := strings.TrimSpace(cfg.Raw["field_name"]); v != "" {
+		cfg.FieldName = v
+	}
+	if v := strings.TrimSpace(cfg.Raw["value"]); v != "" {
+		cfg.Value = v
+	}
+	if v := strings.TrimSpace(cfg.Raw["debug"]); v != "" {
+		cfg.Debug = ParseBool(v)
+	}
+	return cfg
+}
+
+// ParseBool 接受 "1" / "true"（不区分大小写）
+func ParseBool(v string) bool {
+	return v == "1" || strings.EqualFold(v, "true")
+}
@@ -0,0 +1,58 @@
+package driver
+
+import (
+	pdk "github.com/extism/go-pdk"
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+)
+
+// HostFunc 宿主收发函数签名（serial_transceive / tcp_transceive）
+//
+// 驱动自行用 //go:wasmimport 声明宿主函数并传入，避免未使用的导入
+// 出现在 wasm 模块中。
+type HostFunc func(wPtr uint64, wSize uint64, rPtr uint64, rCap uint64, timeoutMs uint64) uint64
+
+// HostTransport 把宿主收发函数适配为 modbus.Transport
+type HostTransport HostFunc
+
+// Transceive 通过宿主函数发送请求，n<=0 视为超时
+func (fn HostTransport) Transceive(req []byte, respCap int, timeoutMs int) ([]byte, error) {
+	if len(req) == 0 || respCap <= 0 {
+		return nil, modbus.ErrTimeout
+	}
+
+	reqMem := pdk.AllocateBytes(req)
+	defer reqMem.Free()
+	respMem := pdk.Allocate(respCap)
+	defer respMem.Free()
+
+	n := int(fn(
+		reqMem.Offset(), uint64(len(req)),
+		respMem.Offset(), uint64(respCap),
+		uint64(timeoutMs),
+	))
+	if n <= 0 {
+		return nil, modbus.ErrTimeout
+	}
+	if n > respCap {
+		n = respCap
+	}
+
+	resp := make([]byte, n)
+	mem := pdk.NewMemory(respMem.Offset(), uint64(n))
+	mem.Load(resp)
+	return resp, nil
+}
+
+// NewRTUClient 基于 serial_transceive 创建 RTU 客户端
+func NewRTUClient(fn HostFunc, debug bool) *modbus.Client {
+	c := modbus.NewRTUClient(HostTransport(fn))
+	c.Logf = DebugLogf(debug)
+	return c
+}
+
+// NewTCPClient 基于 tcp_transceive 创建 Modbus TCP 客户端
+func NewTCPClient(fn HostFunc, debug bool) *modbus.Client {
+	c := modbus.NewTCPClient(HostTransport(fn))
+	c.Logf = DebugLogf(debug)
+	return c
+}
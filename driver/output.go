@@ -0,0 +1,53 @@
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	pdk "github.com/extism/go-pdk"
+)
+
+// OutputJSON 序列化结果并写入插件输出
+func OutputJSON(v interface{}) {
+	b, _ := json.Marshal(v)
+	if len(b) == 0 {
+		b = []byte(`{"success":false,"error":"encode failed"}`)
+	}
+	pdk.Output(b)
+}
+
+// OutputVersion 输出 version 导出函数的标准结果
+func OutputVersion(version string) {
+	OutputJSON(map[string]interface{}{
+		"success": true,
+		"data": map[string]string{
+			"version": version,
+		},
+	})
+}
+
+// Recover 用于 handle 入口的 defer，panic 时输出失败结果
+func Recover() {
+	if r := recover(); r != nil {
+		OutputJSON(map[string]interface{}{"success": false, "error": "panic"})
+	}
+}
+
+// FormatFloat 按小数位格式化测点值
+func FormatFloat(val float64, decimals int) string {
+	return strconv.FormatFloat(val, 'f', decimals, 64)
+}
+
+// Logf 输出调试日志
+func Logf(format string, args ...interface{}) {
+	pdk.Log(pdk.LogDebug, fmt.Sprintf(format, args...))
+}
+
+// DebugLogf debug=true 时返回 Logf，否则返回 nil（供 modbus.Client.Logf 使用）
+func DebugLogf(debug bool) func(format string, args ...interface{}) {
+	if debug {
+		return Logf
+	}
+	return nil
+}
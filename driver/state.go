@@ -0,0 +1,39 @@
+package driver
+
+import (
+	"encoding/json"
+	"strconv"
+
+	pdk "github.com/extism/go-pdk"
+)
+
+// 驱动状态以 JSON 保存在 Extism var 中，跨 handle 调用保留；
+// 插件实例重建（网关重启、驱动重载）后丢失，驱动须能从空状态恢复。
+
+// LoadState 读取 key 对应的状态到 v，不存在或无法解析时返回 false
+func LoadState(key string, v interface{}) bool {
+	b := pdk.GetVar(key)
+	if len(b) == 0 {
+		return false
+	}
+	return json.Unmarshal(b, v) == nil
+}
+
+// SaveState 保存状态
+func SaveState(key string, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	pdk.SetVar(key, b)
+}
+
+// ClearState 删除状态
+func ClearState(key string) {
+	pdk.RemoveVar(key)
+}
+
+// StateKey 按从站地址区分状态，同一插件实例采集多台设备时互不覆盖
+func StateKey(name string, devAddr int) string {
+	return name + "@" + strconv.Itoa(devAddr)
+}
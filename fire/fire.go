@@ -0,0 +1,269 @@
+// Package fire 消防主机测点的状态跟踪：由字段名与标签推导楼层/区域/设备类别，
+// 与上次取值比较生成状态变化事件，并统计火警、故障数。
+//
+// 本包不依赖 Extism，上次取值由驱动保存在 Extism var 中并传入 Track，
+// 可在本机测试。
+package fire
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gonglijing/xunjiFsu/drvs/point"
+)
+
+// 未配置火警码 / 故障码时，按状态含义计入 alarms / faults（点表 配置:states 中的标签）
+const (
+	StateAlarm = "火警"
+	StateFault = "故障"
+)
+
+// deviceTypes 字段名前缀对应的设备类别，长前缀在前
+var deviceTypes = []struct{ prefix, category, name string }{
+	{"lszs", "water_flow", "水流指示器"},
+	{"slzs", "water_flow", "水流指示器"},
+	{"xhdf", "signal_valve", "信号蝶阀"},
+	{"xhf", "signal_valve", "信号阀"},
+	{"sl", "water_flow", "水流指示器"},
+	{"yg", "smoke", "感烟探测器"},
+	{"wg", "heat", "感温探测器"},
+	{"sb", "manual_call_point", "手动报警按钮"},
+	{"sg", "sounder_strobe", "声光警报器"},
+	{"xb", "hydrant_button", "消火栓按钮"},
+}
+
+// deviceSuffixes 标签末尾的设备名，去掉后即为区域（"1层走道手报" → 走道），长的在前
+var deviceSuffixes = []string{"水流指示", "信号蝶阀", "信号阀", "水流", "烟感", "温感", "手报", "声光", "消报"}
+
+// Tags 由字段名与标签推导的测点标签
+type Tags struct {
+	Floor      int    `json:"floor"`       // 0 表示标签中没有楼层
+	Area       string `json:"area"`        // 房间/区域，如 走道、心电
+	Category   string `json:"category"`    // 设备类别，如 smoke
+	DeviceType string `json:"device_type"` // 设备类别中文名
+}
+
+// TagsOf 解析 "1层心电烟感" 与 yg0101 形式的标签和字段名
+func TagsOf(p point.Point) Tags {
+	var t Tags
+	for _, d := range deviceTypes {
+		if strings.HasPrefix(p.Field, d.prefix) {
+			t.Category, t.DeviceType = d.category, d.name
+			break
+		}
+	}
+	area := p.Label
+	if i := strings.Index(area, "层"); i > 0 {
+		if n, err := strconv.Atoi(area[:i]); err == nil {
+			t.Floor = n
+			area = area[i+len("层"):]
+		}
+	}
+	for _, suffix := range deviceSuffixes {
+		if strings.HasSuffix(area, suffix) {
+			area = strings.TrimSuffix(area, suffix)
+			break
+		}
+	}
+	t.Area = area
+	return t
+}
+
+// Event 一个测点的状态变化
+type Event struct {
+	Field string `json:"field_name"`
+	Label string `json:"label"`
+	Tags
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+	OldState string `json:"old_state"`
+	NewState string `json:"new_state"`
+}
+
+// Codes 计入 alarms / faults 的面板取值（火警码、故障码）
+type Codes struct {
+	Alarm []int64
+	Fault []int64
+}
+
+// ParseCodes 解析 config 中的火警码与故障码，如 "1"、"2,3"；均为空时返回零值，
+// 此时按状态含义统计
+func ParseCodes(alarm, fault string) (Codes, error) {
+	var c Codes
+	var err error
+	if c.Alarm, err = parseCodeList(alarm); err != nil {
+		return c, errors.New("alarm_codes: " + err.Error())
+	}
+	if c.Fault, err = parseCodeList(fault); err != nil {
+		return c, errors.New("fault_codes: " + err.Error())
+	}
+	return c, nil
+}
+
+func parseCodeList(s string) ([]int64, error) {
+	var codes []int64
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' || r == '，' || r == ' ' }) {
+		v, err := strconv.ParseInt(item, 0, 64)
+		if err != nil {
+			return nil, errors.New("invalid code " + strconv.Quote(item))
+		}
+		codes = append(codes, v)
+	}
+	return codes, nil
+}
+
+// Configured 是否给出了火警码或故障码
+func (c Codes) Configured() bool {
+	return len(c.Alarm) > 0 || len(c.Fault) > 0
+}
+
+// classify 测点取值是否为火警 / 故障：给出了火警码或故障码时按码判断，
+// 否则按测点的状态含义判断
+func (c Codes) classify(p point.Point, value float64) (alarm, fault bool) {
+	if c.Configured() {
+		return hasCode(c.Alarm, value), hasCode(c.Fault, value)
+	}
+	label, _ := p.StateLabel(value)
+	return label == StateAlarm, label == StateFault
+}
+
+func hasCode(codes []int64, value float64) bool {
+	for _, c := range codes {
+		if float64(c) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Tally 一组测点的汇总；既未给出火警码 / 故障码、点表也未配置状态含义时
+// alarms / faults 为 null（未知）
+type Tally struct {
+	Points int  `json:"points"`
+	Alarms *int `json:"alarms"`
+	Faults *int `json:"faults"`
+	Stale  int  `json:"stale"` // 本次读取失败、不计入 alarms / faults 的测点数
+}
+
+func newTally(counted bool) *Tally {
+	t := &Tally{}
+	if counted {
+		t.Alarms, t.Faults = new(int), new(int)
+	}
+	return t
+}
+
+// add 计入一个测点，good 为本次读取是否有效
+func (t *Tally) add(alarm, fault, good bool) {
+	t.Points++
+	switch {
+	case !good:
+		t.Stale++
+	case t.Alarms == nil:
+	case alarm:
+		*t.Alarms++
+	case fault:
+		*t.Faults++
+	}
+}
+
+// Summary 全部测点的汇总，floors 以楼层号为键，categories 以设备类别为键
+type Summary struct {
+	*Tally
+	Floors     map[string]*Tally `json:"floors"`
+	Categories map[string]*Tally `json:"categories"`
+	counted    bool
+}
+
+func (s Summary) floor(n int) *Tally {
+	return s.group(s.Floors, strconv.Itoa(n))
+}
+
+func (s Summary) category(c string) *Tally {
+	if c == "" {
+		c = "other"
+	}
+	return s.group(s.Categories, c)
+}
+
+func (s Summary) group(m map[string]*Tally, key string) *Tally {
+	t := m[key]
+	if t == nil {
+		t = newTally(s.counted)
+		m[key] = t
+	}
+	return t
+}
+
+// Track 给 points 加上 tags，与 last 中上次的取值比较生成事件并汇总，
+// 本次读取有效的取值写回 last；baseline 为 true（首次轮询）时只记录不产生事件。
+// points 与 table 一一对应（Registers.Collect 的输出）。
+//
+// 取值解码成功（good，或 out_of_range：如火警码不在 states 中）的测点都按
+// 火警码 / 故障码分类并参与比较；读取失败（comm_fail / crc_error / exception）
+// 的测点不更新 last、不产生事件，计入 stale，不沿用上次的状态（避免通信中断时
+// 持续报出或悄悄清除火警）。
+func Track(table []point.Point, points []map[string]interface{}, last map[string]string, baseline bool, codes Codes) ([]Event, Summary) {
+	counted := codes.Configured() || statesMapped(table)
+	events := []Event{}
+	sum := Summary{Tally: newTally(counted), Floors: map[string]*Tally{}, Categories: map[string]*Tally{}, counted: counted}
+	for i, pt := range points {
+		p := table[i]
+		tags := TagsOf(p)
+		pt["tags"] = tags
+
+		value, _ := pt["value"].(string)
+		quality, _ := pt["quality"].(string)
+		good := decoded(quality) && value != ""
+		if good {
+			old, seen := last[p.Field]
+			last[p.Field] = value
+			if !baseline && seen && old != value {
+				events = append(events, Event{
+					Field:    p.Field,
+					Label:    p.Label,
+					Tags:     tags,
+					OldValue: old,
+					NewValue: value,
+					OldState: stateOf(p, old),
+					NewState: stateOf(p, value),
+				})
+			}
+		}
+
+		var alarm, fault bool
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			alarm, fault = codes.classify(p, v)
+		}
+		for _, t := range []*Tally{sum.Tally, sum.floor(tags.Floor), sum.category(tags.Category)} {
+			t.add(alarm, fault, good)
+		}
+	}
+	return events, sum
+}
+
+// decoded 该质量的输出是否带有从设备读到的取值
+func decoded(quality string) bool {
+	return quality == point.QualityGood || quality == point.QualityOutOfRange
+}
+
+// statesMapped 是否有测点配置了状态含义
+func statesMapped(table []point.Point) bool {
+	for _, p := range table {
+		if len(p.States) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// stateOf 输出值对应的状态含义，未定义或为空时返回 ""
+func stateOf(p point.Point, value string) string {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return ""
+	}
+	label, _ := p.StateLabel(v)
+	return label
+}
@@ -0,0 +1,134 @@
+package fire
+
+import (
+	"testing"
+
+	"github.com/gonglijing/xunjiFsu/drvs/point"
+)
+
+var testTable = []point.Point{
+	{Field: "yg0101", Label: "1层心电烟感"},
+	{Field: "sb0132", Label: "1层走道手报"},
+	{Field: "yg0201", Label: "2层走道烟感"},
+	{Field: "sg0273", Label: "3层走道声光"},
+}
+
+func outputs(values ...string) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(values))
+	for i, v := range values {
+		quality := point.QualityGood
+		if v == "" {
+			quality = point.QualityCommFail
+		}
+		out[i] = map[string]interface{}{"field_name": testTable[i].Field, "value": v, "quality": quality}
+	}
+	return out
+}
+
+func count(n *int) interface{} {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
+
+func TestTrackCountsConfiguredCodes(t *testing.T) {
+	codes, err := ParseCodes("1", "2,3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, sum := Track(testTable, outputs("1", "3", "0", ""), map[string]string{}, true, codes)
+
+	if count(sum.Alarms) != 1 || count(sum.Faults) != 1 || sum.Stale != 1 || sum.Points != 4 {
+		t.Errorf("summary = points %d alarms %v faults %v stale %d, want 4/1/1/1",
+			sum.Points, count(sum.Alarms), count(sum.Faults), sum.Stale)
+	}
+	if f := sum.Floors["1"]; count(f.Alarms) != 1 || count(f.Faults) != 1 {
+		t.Errorf("floor 1 = alarms %v faults %v, want 1/1", count(f.Alarms), count(f.Faults))
+	}
+	if f := sum.Floors["3"]; count(f.Alarms) != 0 || f.Stale != 1 {
+		t.Errorf("floor 3 = alarms %v stale %d, want 0/1", count(f.Alarms), f.Stale)
+	}
+	if c := sum.Categories["smoke"]; c.Points != 2 || count(c.Alarms) != 1 {
+		t.Errorf("smoke = points %d alarms %v, want 2/1", c.Points, count(c.Alarms))
+	}
+}
+
+func TestTrackCountsStateLabels(t *testing.T) {
+	states, err := point.ParseStates("0=正常;1=火警;2=故障")
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := make([]point.Point, len(testTable))
+	for i, p := range testTable {
+		p.States = states
+		table[i] = p
+	}
+	_, sum := Track(table, outputs("2", "1", "1", "0"), map[string]string{}, true, Codes{})
+	if count(sum.Alarms) != 2 || count(sum.Faults) != 1 {
+		t.Errorf("alarms %v faults %v, want 2/1", count(sum.Alarms), count(sum.Faults))
+	}
+}
+
+// 火警码不在 states 中时 Output 标为 out_of_range，仍须计入 alarms 并产生事件
+func TestTrackAlarmCodeOutsideStates(t *testing.T) {
+	states, err := point.ParseStates("0=正常;2=故障")
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes, err := ParseCodes("1", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := testTable[0]
+	p.States = states
+	table := []point.Point{p}
+	last := map[string]string{}
+
+	Track(table, []map[string]interface{}{p.Output(0)}, last, true, codes)
+	out := p.Output(1)
+	if out["quality"] != point.QualityOutOfRange {
+		t.Fatalf("quality = %v, want out_of_range", out["quality"])
+	}
+	events, sum := Track(table, []map[string]interface{}{out}, last, false, codes)
+	if count(sum.Alarms) != 1 || sum.Stale != 0 {
+		t.Errorf("alarms %v stale %d, want 1/0", count(sum.Alarms), sum.Stale)
+	}
+	if len(events) != 1 || events[0].NewValue != "1" {
+		t.Errorf("events = %+v, want yg0101 0→1", events)
+	}
+}
+
+func TestTrackUnknownWithoutCodes(t *testing.T) {
+	_, sum := Track(testTable, outputs("1", "0", "0", "0"), map[string]string{}, true, Codes{})
+	if sum.Alarms != nil || sum.Faults != nil || sum.Floors["1"].Alarms != nil {
+		t.Errorf("alarms %v faults %v, want null without codes or states", count(sum.Alarms), count(sum.Faults))
+	}
+}
+
+func TestTrackEvents(t *testing.T) {
+	last := map[string]string{}
+	Track(testTable, outputs("0", "0", "0", "0"), last, true, Codes{})
+
+	// 读取失败的测点不产生事件，也不覆盖上次取值
+	events, _ := Track(testTable, outputs("1", "0", "", "0"), last, false, Codes{Alarm: []int64{1}})
+	if len(events) != 1 || events[0].Field != "yg0101" || events[0].OldValue != "0" || events[0].NewValue != "1" || events[0].Floor != 1 {
+		t.Fatalf("events = %+v, want yg0101 0→1 on floor 1", events)
+	}
+	if last["yg0201"] != "0" {
+		t.Errorf("stale point overwrote last value: %q", last["yg0201"])
+	}
+}
+
+func TestParseCodes(t *testing.T) {
+	if _, err := ParseCodes("1,x", ""); err == nil {
+		t.Error("ParseCodes accepted an invalid alarm code")
+	}
+	c, err := ParseCodes("", "0x02; 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Configured() || len(c.Fault) != 2 || c.Fault[0] != 2 || c.Fault[1] != 3 {
+		t.Errorf("ParseCodes = %+v", c)
+	}
+}
@@ -0,0 +1,57 @@
+// Package generic 通用 Modbus 驱动主体：点表由 config.points 下发，按功能码与
+// 地址合并读请求后换算工程值。
+//
+// 通用/ModbusRTU 与 通用/ModbusTCP 只负责声明各自的宿主收发函数并创建
+// modbus.Client，其余逻辑（配置解析、读取计划、输出）都在本包。
+package generic
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gonglijing/xunjiFsu/drvs/driver"
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+	"github.com/gonglijing/xunjiFsu/drvs/point"
+)
+
+// 单次读取寄存器数量上限（Modbus 规范为 125）
+const defaultMaxRegisters = 125
+
+// ConfigKeys 通用驱动特有的 config 键
+var ConfigKeys = []point.ConfigKey{
+	{Key: "points", Type: "json", Desc: "点表 JSON 数组，字段同 describe 的 points"},
+	{Key: "max_registers", Type: "int", Default: "125", Desc: "单次读取最多寄存器数 1~125"},
+	{Key: "max_gap", Type: "int", Default: "0", Desc: "合并读取时允许跨过的未定义寄存器数"},
+}
+
+// Handle 解析 config.points，读取全部测点并返回 handle 的输出
+func Handle(client *modbus.Client, cfg driver.Config) map[string]interface{} {
+	points, err := point.ParseTable(cfg.Raw["points"])
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}
+	}
+
+	maxCount := configUint(cfg, "max_registers", defaultMaxRegisters, 1, defaultMaxRegisters)
+	maxGap := configUint(cfg, "max_gap", 0, 0, maxCount-1)
+	blocks, err := point.Plan(points, maxCount, maxGap)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}
+	}
+	values, errs := point.ReadPoints(client, byte(cfg.DeviceAddress), blocks, points)
+	return point.Result(values, errs)
+}
+
+// Describe 返回 describe 的 data；未下发点表时 points 为空，仅描述配置项
+func Describe(version, protocol string, cfg driver.Config) point.Schema {
+	points, _ := point.ParseTable(cfg.Raw["points"])
+	return point.Describe(version, protocol, points, ConfigKeys...)
+}
+
+// configUint 解析整数配置项，缺省或超出 [min, max] 时取 def
+func configUint(cfg driver.Config, key string, def, min, max uint16) uint16 {
+	n, err := strconv.Atoi(strings.TrimSpace(cfg.Raw[key]))
+	if err != nil || n < int(min) || n > int(max) {
+		return def
+	}
+	return uint16(n)
+}
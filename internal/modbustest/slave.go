@@ -0,0 +1,91 @@
+// Package modbustest 提供测试用的 Modbus RTU 从站，供 modbus、point 等包的
+// 测试共用。
+package modbustest
+
+import "github.com/gonglijing/xunjiFsu/drvs/modbus"
+
+// Slave 实现 modbus.Transport 的 RTU 从站：FC03/FC04 读 Holding，FC01 读
+// Coils，读到表中没有的地址返回非法数据地址异常；FC05/FC06/FC16 写入后回显，
+// ReadOnly 中的地址拒绝写入。Pulse 为 true 时写入后自动清零（脉冲命令）。
+type Slave struct {
+	Holding  map[uint16]uint16
+	Coils    map[uint16]bool
+	ReadOnly map[uint16]bool
+	Pulse    bool
+	Requests int // 收到的请求数
+}
+
+// NewSlave 创建空表的从站
+func NewSlave() *Slave {
+	return &Slave{Holding: map[uint16]uint16{}, Coils: map[uint16]bool{}, ReadOnly: map[uint16]bool{}}
+}
+
+// Transceive 按请求帧生成带 CRC 的响应帧
+func (s *Slave) Transceive(req []byte, respCap int, timeoutMs int) ([]byte, error) {
+	s.Requests++
+	addr := uint16(req[2])<<8 | uint16(req[3])
+	count := uint16(req[4])<<8 | uint16(req[5])
+	var resp []byte
+	switch req[1] {
+	case modbus.FuncReadCoils:
+		resp = s.readCoils(req, addr, count)
+	case modbus.FuncReadHolding, modbus.FuncReadInput:
+		resp = s.readRegisters(req, addr, count)
+	case modbus.FuncWriteCoil, modbus.FuncWriteSingle, modbus.FuncWriteMultiple:
+		resp = s.write(req, addr)
+	default:
+		resp = exception(req, modbus.ExceptionIllegalFunction)
+	}
+	crc := modbus.CRC16(resp)
+	return append(resp, byte(crc), byte(crc>>8)), nil
+}
+
+func (s *Slave) readRegisters(req []byte, addr, count uint16) []byte {
+	resp := []byte{req[0], req[1], byte(count * 2)}
+	for a := addr; a < addr+count; a++ {
+		v, ok := s.Holding[a]
+		if !ok {
+			return exception(req, modbus.ExceptionIllegalDataAddress)
+		}
+		resp = append(resp, byte(v>>8), byte(v))
+	}
+	return resp
+}
+
+func (s *Slave) readCoils(req []byte, addr, count uint16) []byte {
+	bits := make([]byte, (count+7)/8)
+	for i := uint16(0); i < count; i++ {
+		on, ok := s.Coils[addr+i]
+		if !ok {
+			return exception(req, modbus.ExceptionIllegalDataAddress)
+		}
+		if on {
+			bits[i/8] |= 1 << (i % 8)
+		}
+	}
+	return append([]byte{req[0], req[1], byte(len(bits))}, bits...)
+}
+
+func (s *Slave) write(req []byte, addr uint16) []byte {
+	if s.ReadOnly[addr] {
+		return exception(req, modbus.ExceptionIllegalDataAddress)
+	}
+	switch {
+	case req[1] == modbus.FuncWriteCoil:
+		s.Coils[addr] = req[4] == 0xFF && !s.Pulse
+	case s.Pulse:
+		s.Holding[addr] = 0
+	case req[1] == modbus.FuncWriteSingle:
+		s.Holding[addr] = uint16(req[4])<<8 | uint16(req[5])
+	default:
+		count := uint16(req[4])<<8 | uint16(req[5])
+		for i := uint16(0); i < count; i++ {
+			s.Holding[addr+i] = uint16(req[7+2*i])<<8 | uint16(req[8+2*i])
+		}
+	}
+	return append([]byte{}, req[:6]...)
+}
+
+func exception(req []byte, code byte) []byte {
+	return []byte{req[0], req[1] | 0x80, code}
+}
@@ -0,0 +1,205 @@
+package pointtable
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// linear 表示 a*v + b，用有理数保存以便生成 1.0 / 9800 这类精确字面量
+type linear struct {
+	a, b *big.Rat
+}
+
+func constant(r *big.Rat) linear { return linear{a: new(big.Rat), b: r} }
+
+func (l linear) isConst() bool { return l.a.Sign() == 0 }
+
+// conversion 把点表表达式化简为 Scale/Offset/Mask。
+//
+// 支持的形式：v、v/10、v*0.1、v/10-40、(v-101665)/9800 以及它们的组合，
+// 要求结果对 v 线性；bitand(v,N) 只在构成整个表达式时化简为 Mask。
+type conversion struct {
+	Scale  *big.Rat
+	Offset *big.Rat
+	Mask   uint16
+}
+
+func parseConversion(expr string) (conversion, error) {
+	p := &exprParser{src: strings.ReplaceAll(expr, " ", "")}
+	if p.src == "" {
+		p.src = "v"
+	}
+	if strings.HasPrefix(p.src, "bitand(") {
+		p.pos = len("bitand")
+		mask, err := p.bitand()
+		if err == nil && p.pos != len(p.src) {
+			err = fmt.Errorf("bitand(v,N) must be the whole expression")
+		}
+		if err != nil {
+			return conversion{}, fmt.Errorf("expression %q: %v", expr, err)
+		}
+		return conversion{Scale: big.NewRat(1, 1), Offset: new(big.Rat), Mask: mask}, nil
+	}
+	l, err := p.parseSum()
+	if err != nil {
+		return conversion{}, fmt.Errorf("expression %q: %v", expr, err)
+	}
+	if p.pos != len(p.src) {
+		return conversion{}, fmt.Errorf("expression %q: unexpected %q", expr, p.src[p.pos:])
+	}
+	if l.isConst() {
+		return conversion{}, fmt.Errorf("expression %q: does not use v", expr)
+	}
+	return conversion{Scale: l.a, Offset: l.b}, nil
+}
+
+type exprParser struct {
+	src string
+	pos int
+}
+
+func (p *exprParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *exprParser) parseSum() (linear, error) {
+	l, err := p.parseProduct()
+	if err != nil {
+		return l, err
+	}
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return l, nil
+		}
+		p.pos++
+		r, err := p.parseProduct()
+		if err != nil {
+			return l, err
+		}
+		if op == '+' {
+			l = linear{a: new(big.Rat).Add(l.a, r.a), b: new(big.Rat).Add(l.b, r.b)}
+		} else {
+			l = linear{a: new(big.Rat).Sub(l.a, r.a), b: new(big.Rat).Sub(l.b, r.b)}
+		}
+	}
+}
+
+func (p *exprParser) parseProduct() (linear, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return l, err
+	}
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' {
+			return l, nil
+		}
+		p.pos++
+		r, err := p.parseUnary()
+		if err != nil {
+			return l, err
+		}
+		switch {
+		case op == '/' && !r.isConst():
+			return l, fmt.Errorf("division by v is not linear")
+		case op == '/' && r.b.Sign() == 0:
+			return l, fmt.Errorf("division by zero")
+		case op == '/':
+			inv := new(big.Rat).Inv(r.b)
+			l = linear{a: new(big.Rat).Mul(l.a, inv), b: new(big.Rat).Mul(l.b, inv)}
+		case l.isConst():
+			l = linear{a: new(big.Rat).Mul(r.a, l.b), b: new(big.Rat).Mul(r.b, l.b)}
+		case r.isConst():
+			l = linear{a: new(big.Rat).Mul(l.a, r.b), b: new(big.Rat).Mul(l.b, r.b)}
+		default:
+			return l, fmt.Errorf("v*v is not linear")
+		}
+	}
+}
+
+func (p *exprParser) parseUnary() (linear, error) {
+	if p.peek() == '-' {
+		p.pos++
+		l, err := p.parseUnary()
+		return linear{a: new(big.Rat).Neg(l.a), b: new(big.Rat).Neg(l.b)}, err
+	}
+	return p.parseAtom()
+}
+
+func (p *exprParser) parseAtom() (linear, error) {
+	switch ch := p.peek(); {
+	case ch == '(':
+		p.pos++
+		l, err := p.parseSum()
+		if err != nil {
+			return l, err
+		}
+		if p.peek() != ')' {
+			return l, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return l, nil
+	case ch >= '0' && ch <= '9' || ch == '.':
+		r, err := p.number()
+		return constant(r), err
+	case unicode.IsLetter(rune(ch)):
+		name := p.ident()
+		switch name {
+		case "v":
+			return linear{a: big.NewRat(1, 1), b: new(big.Rat)}, nil
+		case "bitand":
+			return linear{}, fmt.Errorf("bitand(v,N) must be the whole expression")
+		}
+		return linear{}, fmt.Errorf("unsupported identifier %q", name)
+	case ch == 0:
+		return linear{}, fmt.Errorf("unexpected end")
+	default:
+		return linear{}, fmt.Errorf("unexpected %q", string(ch))
+	}
+}
+
+// bitand(v,N)：只允许作用于 v 本身，对应 Point.Mask
+func (p *exprParser) bitand() (uint16, error) {
+	if !strings.HasPrefix(p.src[p.pos:], "(v,") {
+		return 0, fmt.Errorf("bitand must be bitand(v,N)")
+	}
+	p.pos += 3
+	r, err := p.number()
+	if err != nil {
+		return 0, err
+	}
+	if p.peek() != ')' {
+		return 0, fmt.Errorf("missing )")
+	}
+	p.pos++
+	if !r.IsInt() || r.Sign() <= 0 || r.Num().BitLen() > 16 {
+		return 0, fmt.Errorf("bitand mask %s out of range", r.RatString())
+	}
+	return uint16(r.Num().Uint64()), nil
+}
+
+func (p *exprParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.src) && (unicode.IsLetter(rune(p.src[p.pos])) || unicode.IsDigit(rune(p.src[p.pos])) || p.src[p.pos] == '_') {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *exprParser) number() (*big.Rat, error) {
+	start := p.pos
+	for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+		p.pos++
+	}
+	r, ok := new(big.Rat).SetString(p.src[start:p.pos])
+	if !ok {
+		return nil, fmt.Errorf("bad number %q", p.src[start:p.pos])
+	}
+	return r, nil
+}
@@ -0,0 +1,210 @@
+package pointtable
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+	"github.com/gonglijing/xunjiFsu/drvs/point"
+)
+
+// 驱动源码中的生成区标记，开始行 pointgen 之后可跟选项（如 -max 50）
+const (
+	BeginMarker = "// 【自动生成】pointgen"
+	EndMarker   = "// 【自动生成】结束"
+)
+
+// Region 驱动文件中的生成区
+type Region struct {
+	begin, end int    // 生成内容在文件中的字节范围（不含两行标记）
+	Options    string // 开始标记中的选项
+}
+
+// FindRegion 查找驱动源码中的生成区，没有标记时返回 false
+func FindRegion(src []byte) (Region, bool, error) {
+	var r Region
+	b := bytes.Index(src, []byte(BeginMarker))
+	if b < 0 {
+		return r, false, nil
+	}
+	if b > 0 && src[b-1] != '\n' {
+		return r, false, fmt.Errorf("begin marker must start a line")
+	}
+	nl := bytes.IndexByte(src[b:], '\n')
+	if nl < 0 {
+		return r, false, fmt.Errorf("missing %q", EndMarker)
+	}
+	r.Options = strings.TrimSpace(string(src[b+len(BeginMarker) : b+nl]))
+	r.begin = b + nl + 1
+	e := bytes.Index(src[r.begin:], []byte(EndMarker))
+	if e < 0 {
+		return r, false, fmt.Errorf("missing %q", EndMarker)
+	}
+	r.end = r.begin + e
+	if bytes.Contains(src[r.end+len(EndMarker):], []byte(BeginMarker)) {
+		return r, false, fmt.Errorf("more than one generated Region")
+	}
+	return r, true, nil
+}
+
+// Render 生成 pointConfig 与 readBlocks 源码
+func Render(entries []Entry, blocks []modbus.Block) string {
+	var sb strings.Builder
+	sb.WriteString("// 由 points.xlsx 生成，请勿手工修改；修改点表后执行 make points\n")
+	sb.WriteString("var pointConfig = []point.Point{\n")
+	for _, e := range entries {
+		sb.WriteString("\t" + renderPoint(e) + ",\n")
+	}
+	sb.WriteString("}\n\n")
+	sb.WriteString("// 读取分组\n")
+	sb.WriteString("var readBlocks = []modbus.Block{\n")
+	for _, b := range blocks {
+		fmt.Fprintf(&sb, "\t{FuncCode: %s, Start: %d, Count: %d},\n", funcCodeName(b.FuncCode), b.Start, b.Count)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func renderPoint(e Entry) string {
+	fields := []string{
+		"Field: " + strconv.Quote(e.Field),
+		"FuncCode: " + funcCodeName(e.FuncCode),
+		"Address: " + strconv.Itoa(int(e.Address)),
+		"Length: " + strconv.Itoa(int(e.Length)),
+		"DataType: " + dataTypeName(e.DataType),
+	}
+	if e.Order != "" {
+		fields = append(fields, "Order: point.Order"+e.Order)
+	}
+	if e.Mask != 0 {
+		fields = append(fields, fmt.Sprintf("Mask: 0x%04X", e.Mask))
+	}
+	if e.Bits != 0 {
+		fields = append(fields, "Bit: "+strconv.Itoa(int(e.Bit)), "Bits: "+strconv.Itoa(int(e.Bits)))
+	}
+	if !e.Eval {
+		fields = append(fields, "Scale: "+ratLiteral(e.scale))
+	}
+	if e.offset.Sign() != 0 {
+		fields = append(fields, "Offset: "+offsetLiteral(e.offset, e.scale))
+	}
+	fields = append(fields, "Expr: "+strconv.Quote(e.Expr))
+	if e.Eval {
+		fields = append(fields, "Eval: true")
+	}
+	fields = append(fields,
+		"Decimals: "+strconv.Itoa(e.Decimals),
+		"RW: "+strconv.Quote(e.RW),
+		"Unit: "+strconv.Quote(e.Unit),
+		"Label: "+strconv.Quote(e.Label),
+	)
+	if len(e.States) > 0 {
+		fields = append(fields, "States: "+statesLiteral(e))
+	}
+	if e.hasMin {
+		fields = append(fields, "Min: "+strconv.FormatFloat(e.Min, 'g', -1, 64))
+	}
+	if e.hasMax {
+		fields = append(fields, "Max: "+strconv.FormatFloat(e.Max, 'g', -1, 64))
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+func funcCodeName(fc byte) string {
+	switch fc {
+	case modbus.FuncReadHolding:
+		return "modbus.FuncReadHolding"
+	case modbus.FuncReadInput:
+		return "modbus.FuncReadInput"
+	}
+	return fmt.Sprintf("0x%02X", fc)
+}
+
+// dataTypeNames 数据类型对应的 point 包常量名
+var dataTypeNames = map[string]string{
+	point.TypeInt16:   "point.TypeInt16",
+	point.TypeUint32:  "point.TypeUint32",
+	point.TypeInt32:   "point.TypeInt32",
+	point.TypeUint64:  "point.TypeUint64",
+	point.TypeInt64:   "point.TypeInt64",
+	point.TypeFloat32: "point.TypeFloat32",
+	point.TypeFloat64: "point.TypeFloat64",
+	point.TypeBCD16:   "point.TypeBCD16",
+	point.TypeBCD32:   "point.TypeBCD32",
+	point.TypeString:  "point.TypeString",
+}
+
+func dataTypeName(t string) string {
+	if name, ok := dataTypeNames[t]; ok {
+		return name
+	}
+	return "point.TypeUint16"
+}
+
+// statePresetNames 状态集名称对应的 point 包变量
+var statePresetNames = map[string]string{
+	"switch": "point.StatesSwitch",
+	"alarm":  "point.StatesAlarm",
+}
+
+func statesLiteral(e Entry) string {
+	if name, ok := statePresetNames[e.statesPreset]; ok {
+		return name
+	}
+	items := make([]string, len(e.States))
+	for i, st := range e.States {
+		items[i] = fmt.Sprintf("{Value: %d, Label: %s}", st.Value, strconv.Quote(st.Label))
+	}
+	return "[]point.State{" + strings.Join(items, ", ") + "}"
+}
+
+// ratLiteral 有限小数写成小数，否则写成 1.0 / 9800 形式以免丢精度
+func ratLiteral(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	d := new(big.Int).Set(r.Denom())
+	digits := 0
+	for _, f := range []int64{2, 5} {
+		q, m := new(big.Int), new(big.Int)
+		for {
+			q.DivMod(d, big.NewInt(f), m)
+			if m.Sign() != 0 {
+				break
+			}
+			d.Set(q)
+			digits++
+		}
+	}
+	if d.Cmp(big.NewInt(1)) == 0 {
+		s := r.FloatString(digits)
+		return strings.TrimRight(s, "0")
+	}
+	return r.Num().String() + ".0 / " + r.Denom().String()
+}
+
+// offsetLiteral 对 (v-101665)/9800 这类表达式沿用 Scale 的分母，写成 -101665.0 / 9800
+func offsetLiteral(offset, scale *big.Rat) string {
+	lit := ratLiteral(offset)
+	if !strings.Contains(lit, "/") || scale.Num().Cmp(big.NewInt(1)) != 0 {
+		return lit
+	}
+	n := new(big.Rat).Mul(offset, new(big.Rat).SetInt(scale.Denom()))
+	if !n.IsInt() {
+		return lit
+	}
+	return n.Num().String() + ".0 / " + scale.Denom().String()
+}
+
+// Rewrite 用新生成的内容替换生成区并 gofmt 整个文件
+func Rewrite(src []byte, r Region, body string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(src[:r.begin])
+	buf.WriteString(body)
+	buf.Write(src[r.end:])
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,270 @@
+// Package pointtable 读写 points.xlsx 点表，并生成驱动源码中的 pointConfig / readBlocks，
+// 供 cmd/pointgen 与 cmd/tslimport 共用。
+package pointtable
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+	"github.com/gonglijing/xunjiFsu/drvs/point"
+)
+
+// points.xlsx 表头
+const (
+	ColLabel    = "属性名"
+	ColField    = "属性标识"
+	ColFunc     = "功能码"
+	ColAddress  = "寄存器地址"
+	ColLength   = "寄存器数量"
+	ColDecimals = "有效小数位"
+	ColPlatType = "数据类型" // 平台存储类型（int64/INIT16…），不决定寄存器解析方式
+	ColRW       = "读写模式"
+	ColUnit     = "单位"
+	ColExpr     = "配置:expression" // 线性时化简为 Scale/Offset/Mask，否则原样保留并在采集时求值
+	ColDataType = "配置:data_type"  // 寄存器解析类型 uint16/int16/uint32/int32/float32/bcd16/string…，可空
+	ColOrder    = "配置:byte_order" // 多寄存器字节序 ABCD/CDAB/BADC/DCBA，可空
+	ColBit      = "配置:bit"        // 位字段起始位（0 为最低位），与 配置:bits 同用
+	ColBits     = "配置:bits"       // 位字段宽度，可空
+	ColStates   = "配置:states"     // 状态量：状态集名称（switch/alarm）或 0=分闸;1=合闸，可空
+	ColMin      = "配置:min"
+	ColMax      = "配置:max"
+)
+
+var requiredColumns = []string{ColLabel, ColField, ColFunc, ColAddress}
+
+// Columns 新建点表时的列顺序
+var Columns = []string{ColLabel, ColField, ColFunc, ColAddress, ColLength, ColDecimals, ColPlatType, ColRW, ColExpr, ColUnit, ColDataType}
+
+// Entry 一行点表，Scale/Offset 额外保留有理数形式用于生成字面量
+type Entry struct {
+	point.Point
+	scale, offset *big.Rat
+	statesPreset  string // 配置:states 为状态集名称时保留，生成时引用 point 包变量
+	hasMin        bool
+	hasMax        bool
+}
+
+// Parse 把工作表转换为点表条目
+func Parse(rows [][]string) ([]Entry, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("empty sheet")
+	}
+	cols := map[string]int{}
+	for i, h := range rows[0] {
+		if h != "" {
+			cols[h] = i
+		}
+	}
+	for _, c := range requiredColumns {
+		if _, ok := cols[c]; !ok {
+			return nil, fmt.Errorf("missing column %s", c)
+		}
+	}
+
+	var entries []Entry
+	seen := map[string]int{}
+	for n, row := range rows[1:] {
+		line := n + 2
+		get := func(col string) string {
+			if i, ok := cols[col]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+		if get(ColField) == "" {
+			continue
+		}
+		e, err := parseRow(get)
+		if err != nil {
+			return nil, fmt.Errorf("row %d (%s): %v", line, get(ColField), err)
+		}
+		if prev, dup := seen[e.Field]; dup {
+			return nil, fmt.Errorf("row %d: duplicate field %s (row %d)", line, e.Field, prev)
+		}
+		seen[e.Field] = line
+		entries = append(entries, e)
+	}
+	points := make([]point.Point, len(entries))
+	for i, e := range entries {
+		points[i] = e.Point
+	}
+	if err := point.CheckRefs(points); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func parseRow(get func(string) string) (Entry, error) {
+	var e Entry
+	e.Field = get(ColField)
+	e.Label = get(ColLabel)
+	e.Unit = get(ColUnit)
+	e.RW = strings.ToUpper(get(ColRW))
+	if e.RW == "" {
+		e.RW = "R"
+	}
+
+	fc, err := parseFuncCode(get(ColFunc))
+	if err != nil {
+		return e, err
+	}
+	e.FuncCode = fc
+
+	addr, err := parseUint(get(ColAddress), 0xFFFF)
+	if err != nil {
+		return e, fmt.Errorf("address: %v", err)
+	}
+	e.Address = uint16(addr)
+
+	length := uint64(1)
+	if s := get(ColLength); s != "" {
+		if length, err = parseUint(s, 125); err != nil || length == 0 {
+			return e, fmt.Errorf("length %q: want 1~125", s)
+		}
+	}
+	e.Length = uint16(length)
+
+	if s := get(ColDecimals); s != "" {
+		d, err := parseUint(s, 9)
+		if err != nil {
+			return e, fmt.Errorf("decimals: %v", err)
+		}
+		e.Decimals = int(d)
+	}
+
+	e.DataType, err = dataType(get(ColDataType), get(ColPlatType), e.Length)
+	if err != nil {
+		return e, err
+	}
+	// 字符串的寄存器数由点表给出，其余类型定长
+	if want := (point.Point{DataType: e.DataType}).Count(); e.DataType != point.TypeString && want != e.Length {
+		return e, fmt.Errorf("data type %s needs %d registers, sheet says %d", e.DataType, want, e.Length)
+	}
+	e.Order = strings.ToUpper(get(ColOrder))
+	if !point.ValidOrder(e.Order) {
+		return e, fmt.Errorf("byte order %q: want ABCD, CDAB, BADC or DCBA", get(ColOrder))
+	}
+	if e.Order == point.OrderABCD {
+		e.Order = ""
+	}
+
+	e.Expr = get(ColExpr)
+	conv, err := parseConversion(e.Expr)
+	if err != nil {
+		// 非线性或引用其他测点的公式原样保留，采集时求值
+		if _, cerr := point.CompileExpr(e.Expr); cerr != nil {
+			return e, err
+		}
+		e.Eval = true
+		conv = conversion{Scale: big.NewRat(1, 1), Offset: new(big.Rat)}
+	}
+	if conv.Mask != 0 && e.Count() > 1 {
+		// Mask 只作用于单个寄存器，多寄存器类型按表达式求值
+		e.Eval = true
+		conv.Mask = 0
+	}
+	if e.Expr == "" {
+		e.Expr = "v"
+	}
+	e.scale, e.offset, e.Mask = conv.Scale, conv.Offset, conv.Mask
+	e.Scale, _ = conv.Scale.Float64()
+	e.Offset, _ = conv.Offset.Float64()
+
+	if s := get(ColBits); s != "" {
+		bits, err := parseUint(s, 64)
+		if err != nil {
+			return e, fmt.Errorf("bits: %v", err)
+		}
+		e.Bits = uint8(bits)
+	}
+	if s := get(ColBit); s != "" {
+		bit, err := parseUint(s, 63)
+		if err != nil {
+			return e, fmt.Errorf("bit: %v", err)
+		}
+		e.Bit = uint8(bit)
+	}
+	if err := e.CheckBits(); err != nil {
+		return e, err
+	}
+
+	if e.States, err = point.ParseStates(get(ColStates)); err != nil {
+		return e, err
+	}
+	if _, ok := point.StatePresets[get(ColStates)]; ok {
+		e.statesPreset = get(ColStates)
+	}
+
+	if s := get(ColMin); s != "" {
+		if e.Min, err = strconv.ParseFloat(s, 64); err != nil {
+			return e, fmt.Errorf("min: %v", err)
+		}
+		e.hasMin = true
+	}
+	if s := get(ColMax); s != "" {
+		if e.Max, err = strconv.ParseFloat(s, 64); err != nil {
+			return e, fmt.Errorf("max: %v", err)
+		}
+		e.hasMax = true
+	}
+	return e, nil
+}
+
+func parseFuncCode(s string) (byte, error) {
+	switch strings.ToUpper(s) {
+	case "HOLDING_REGISTER", "3", "03", "0X03":
+		return modbus.FuncReadHolding, nil
+	case "INPUT_REGISTER", "4", "04", "0X04":
+		return modbus.FuncReadInput, nil
+	}
+	return 0, fmt.Errorf("unsupported function code %q", s)
+}
+
+// dataType 优先取 配置:data_type；平台类型列只在写成寄存器类型且与寄存器数量一致时采用
+// （平台常把 1 个寄存器的值存为 int64），否则按寄存器数量推断无符号类型
+func dataType(explicit, platform string, length uint16) (string, error) {
+	if t := strings.ToLower(explicit); t != "" {
+		if !point.ValidType(t) {
+			return "", fmt.Errorf("unsupported data type %q", explicit)
+		}
+		return t, nil
+	}
+	if t := strings.ToLower(platform); t != point.TypeString && point.ValidType(t) &&
+		(point.Point{DataType: t}).Count() == length {
+		return t, nil
+	}
+	switch length {
+	case 1:
+		return point.TypeUint16, nil
+	case 2:
+		return point.TypeUint32, nil
+	case 4:
+		return point.TypeUint64, nil
+	}
+	return "", fmt.Errorf("length %d needs an explicit %s", length, ColDataType)
+}
+
+func parseUint(s string, max uint64) (uint64, error) {
+	// 数值单元格可能带 ".0"
+	s = strings.TrimSuffix(s, ".0")
+	v, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return 0, err
+	}
+	if v > max {
+		return 0, fmt.Errorf("%d exceeds %d", v, max)
+	}
+	return v, nil
+}
+
+// Plan 见 point.Plan
+func Plan(entries []Entry, maxCount, maxGap uint16) ([]modbus.Block, error) {
+	points := make([]point.Point, len(entries))
+	for i, e := range entries {
+		points[i] = e.Point
+	}
+	return point.Plan(points, maxCount, maxGap)
+}
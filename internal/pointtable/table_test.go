@@ -0,0 +1,84 @@
+package pointtable
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gonglijing/xunjiFsu/drvs/point"
+)
+
+func TestParseDataTypes(t *testing.T) {
+	header := []string{ColLabel, ColField, ColFunc, ColAddress, ColLength, ColDataType}
+	tests := []struct {
+		name    string
+		row     []string
+		want    string
+		words   uint16
+		wantErr string
+	}{
+		{name: "string", row: []string{"型号", "MODEL", "3", "10", "8", "string"}, want: point.TypeString, words: 8},
+		{name: "inferred uint16", row: []string{"电压", "U", "3", "1", "1", ""}, want: point.TypeUint16, words: 1},
+		{name: "float32", row: []string{"功率", "P", "4", "2", "2", "float32"}, want: point.TypeFloat32, words: 2},
+		{name: "length mismatch", row: []string{"功率", "P", "3", "2", "1", "float32"}, wantErr: "data type float32 needs 2 registers, sheet says 1"},
+		{name: "string too long", row: []string{"型号", "MODEL", "3", "10", "126", "string"}, wantErr: "want 1~125"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Parse([][]string{header, tt.row})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := entries[0]; got.Type() != tt.want || got.Count() != tt.words {
+				t.Errorf("type %s count %d, want %s count %d", got.Type(), got.Count(), tt.want, tt.words)
+			}
+		})
+	}
+}
+
+func TestRenderStringPoint(t *testing.T) {
+	entries, err := Parse([][]string{
+		{ColLabel, ColField, ColFunc, ColAddress, ColLength, ColDataType},
+		{"型号", "MODEL", "3", "10", "8", "string"},
+		{"电压", "U", "3", "18", "1", ""},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks, err := Plan(entries, 125, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 1 || blocks[0].Start != 10 || blocks[0].Count != 9 {
+		t.Errorf("Plan = %+v, want one block 10+9", blocks)
+	}
+	if src := Render(entries, blocks); !strings.Contains(src, "DataType: point.TypeString") || !strings.Contains(src, "Length: 8") {
+		t.Errorf("Render lost the string type or length:\n%s", src)
+	}
+}
+
+func TestParseBitand(t *testing.T) {
+	header := []string{ColLabel, ColField, ColFunc, ColAddress, ColLength, ColDataType, ColExpr}
+	entries, err := Parse([][]string{
+		header,
+		{"开关", "SW", "3", "0", "1", "", "bitand(v,32768)"},
+		{"组合", "MIX", "3", "1", "1", "", "v+bitand(v,1)"},
+		{"计数", "CNT", "3", "2", "2", "uint32", "bitand(v,255)"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := entries[0]; e.Mask != 0x8000 || e.Eval {
+		t.Errorf("SW: mask %#x eval %v, want 0x8000 without eval", e.Mask, e.Eval)
+	}
+	for _, e := range entries[1:] {
+		if e.Mask != 0 || !e.Eval {
+			t.Errorf("%s: mask %#x eval %v, want expression evaluation", e.Field, e.Mask, e.Eval)
+		}
+	}
+}
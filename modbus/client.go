@@ -0,0 +1,218 @@
+// Package modbus 提供驱动共用的 Modbus RTU / TCP 客户端。
+//
+// 本包不依赖 Extism，收发由 Transport 完成（驱动侧用宿主函数
+// serial_transceive / tcp_transceive 实现），可在 TinyGo 下编译。
+package modbus
+
+const (
+	FuncReadCoils     = 0x01 // 读线圈
+	FuncReadHolding   = 0x03 // 读保持寄存器
+	FuncReadInput     = 0x04 // 读输入寄存器
+	FuncWriteCoil     = 0x05 // 写单个线圈
+	FuncWriteSingle   = 0x06 // 写单个寄存器
+	FuncWriteMultiple = 0x10 // 写多个寄存器
+)
+
+// DefaultTimeoutMs 单次请求默认超时
+const DefaultTimeoutMs = 1000
+
+// MaxReadCount FC03/FC04 单次最多读取的寄存器数（协议上限）
+const MaxReadCount = 125
+
+// Block 一次读请求覆盖的寄存器段
+type Block struct {
+	FuncCode byte
+	Start    uint16
+	Count    uint16
+}
+
+// Transport 发送一帧请求并返回响应，respCap 为期望的最大响应长度
+type Transport interface {
+	Transceive(req []byte, respCap int, timeoutMs int) ([]byte, error)
+}
+
+// Mode 帧格式
+type Mode int
+
+const (
+	RTU Mode = iota
+	TCP
+)
+
+// Client Modbus 主站
+type Client struct {
+	Mode      Mode
+	Transport Transport
+	TimeoutMs int
+	// Logf 非空时输出请求/响应调试日志
+	Logf func(format string, args ...interface{})
+
+	tid uint16 // Modbus TCP 事务标识，每个请求递增
+}
+
+// NewRTUClient 创建串口 RTU 客户端
+func NewRTUClient(t Transport) *Client {
+	return &Client{Mode: RTU, Transport: t, TimeoutMs: DefaultTimeoutMs}
+}
+
+// NewTCPClient 创建 Modbus TCP 客户端
+func NewTCPClient(t Transport) *Client {
+	return &Client{Mode: TCP, Transport: t, TimeoutMs: DefaultTimeoutMs}
+}
+
+// ReadHoldingRegisters FC03 读保持寄存器
+func (c *Client) ReadHoldingRegisters(slave byte, start uint16, count uint16) ([]uint16, error) {
+	return c.ReadRegisters(slave, FuncReadHolding, start, count)
+}
+
+// ReadInputRegisters FC04 读输入寄存器
+func (c *Client) ReadInputRegisters(slave byte, start uint16, count uint16) ([]uint16, error) {
+	return c.ReadRegisters(slave, FuncReadInput, start, count)
+}
+
+// ReadRegisters 以指定功能码读取 count 个寄存器，返回值长度恰为 count
+func (c *Client) ReadRegisters(slave byte, funcCode byte, start uint16, count uint16) ([]uint16, error) {
+	var req []byte
+	var respCap int
+	tid := c.nextTID()
+	if c.Mode == TCP {
+		req = BuildTCPRead(tid, slave, funcCode, start, count)
+		respCap = TCPReadRespLen(count)
+	} else {
+		req = BuildRTURead(slave, funcCode, start, count)
+		respCap = RTUReadRespLen(count)
+	}
+
+	resp, err := c.transceive(req, respCap)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []uint16
+	if c.Mode == TCP {
+		values, err = ParseTCPRead(resp, tid, slave, funcCode)
+	} else {
+		values, err = ParseRTURead(resp, slave, funcCode)
+	}
+	if err != nil {
+		c.logf("parse err=%v", err)
+		return nil, err
+	}
+	if len(values) < int(count) {
+		return nil, ErrInsufficientData
+	}
+	return values[:count], nil
+}
+
+// ReadCoils FC01 读 count 个线圈
+func (c *Client) ReadCoils(slave byte, start uint16, count uint16) ([]bool, error) {
+	var req []byte
+	var respCap int
+	tid := c.nextTID()
+	if c.Mode == TCP {
+		req = BuildTCPRead(tid, slave, FuncReadCoils, start, count)
+		respCap = TCPCoilsRespLen(count)
+	} else {
+		req = BuildRTURead(slave, FuncReadCoils, start, count)
+		respCap = RTUCoilsRespLen(count)
+	}
+
+	resp, err := c.transceive(req, respCap)
+	if err != nil {
+		return nil, err
+	}
+
+	var bits []bool
+	if c.Mode == TCP {
+		bits, err = ParseTCPCoils(resp, tid, slave, FuncReadCoils, count)
+	} else {
+		bits, err = ParseRTUCoils(resp, slave, FuncReadCoils, count)
+	}
+	if err != nil {
+		c.logf("parse err=%v", err)
+		return nil, err
+	}
+	return bits, nil
+}
+
+// WriteSingleCoil FC05 写单个线圈（ON=0xFF00，OFF=0x0000），校验从站回显
+func (c *Client) WriteSingleCoil(slave byte, addr uint16, on bool) error {
+	if c.Mode == TCP {
+		return c.writeTCP(BuildTCPWriteCoil(c.nextTID(), slave, addr, on))
+	}
+	return c.writeRTU(BuildRTUWriteCoil(slave, addr, on))
+}
+
+// WriteSingleRegister FC06 写单个寄存器，校验从站回显
+func (c *Client) WriteSingleRegister(slave byte, reg uint16, value uint16) error {
+	if c.Mode == TCP {
+		return c.writeTCP(BuildTCPWriteSingle(c.nextTID(), slave, reg, value))
+	}
+	return c.writeRTU(BuildRTUWriteSingle(slave, reg, value))
+}
+
+func (c *Client) writeTCP(req []byte) error {
+	resp, err := c.transceive(req, TCPWriteRespLen)
+	if err != nil {
+		return err
+	}
+	return ParseTCPWrite(resp, req)
+}
+
+func (c *Client) writeRTU(req []byte) error {
+	resp, err := c.transceive(req, RTUWriteRespLen)
+	if err != nil {
+		return err
+	}
+	return ParseRTUWrite(resp, req)
+}
+
+// WriteMultipleRegisters FC16 从 start 起连续写入 values
+func (c *Client) WriteMultipleRegisters(slave byte, start uint16, values []uint16) error {
+	if c.Mode == TCP {
+		return c.writeTCP(BuildTCPWriteMultiple(c.nextTID(), slave, start, values))
+	}
+	return c.writeRTU(BuildRTUWriteMultiple(slave, start, values))
+}
+
+func (c *Client) transceive(req []byte, respCap int) ([]byte, error) {
+	timeout := c.TimeoutMs
+	if timeout <= 0 {
+		timeout = DefaultTimeoutMs
+	}
+	c.logf("req fc=%02X % X", req[c.funcOffset()], req)
+	resp, err := c.Transport.Transceive(req, respCap, timeout)
+	c.logf("resp n=%d % X", len(resp), preview(resp, 24))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) == 0 {
+		return nil, ErrTimeout
+	}
+	return resp, nil
+}
+
+func (c *Client) nextTID() uint16 {
+	c.tid++
+	return c.tid
+}
+
+func (c *Client) funcOffset() int {
+	if c.Mode == TCP {
+		return 7
+	}
+	return 1
+}
+
+func (c *Client) logf(format string, args ...interface{}) {
+	if c.Logf != nil {
+		c.Logf(format, args...)
+	}
+}
+
+func preview(b []byte, max int) []byte {
+	if len(b) > max {
+		return b[:max]
+	}
+	return b
+}
@@ -0,0 +1,117 @@
+package modbus_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gonglijing/xunjiFsu/drvs/internal/modbustest"
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+)
+
+func TestLookupCommand(t *testing.T) {
+	control := `{"reset": {"func_code": 5, "address": 1},
+		"mute": {"func_code": 3, "address": 2},
+		"isolate": {"func_code": 6, "offset": 1000, "value": 0},
+		"enable": {"func_code": 6, "offset": 1000, "verify": false}}`
+
+	cmd, err := modbus.LookupCommand(control, "reset", false, modbus.FuncWriteCoil, modbus.FuncWriteSingle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Verify || cmd.WriteValue() != 1 || *cmd.Address != 1 {
+		t.Errorf("reset = %+v, want verify false, value 1", cmd)
+	}
+	// 未配置 verify 时取驱动给出的缺省值，配置后按命令覆盖
+	cmd, err = modbus.LookupCommand(control, "isolate", true, modbus.FuncWriteCoil, modbus.FuncWriteSingle)
+	if err != nil || !cmd.Verify || cmd.WriteValue() != 0 || cmd.Offset != 1000 {
+		t.Errorf("isolate = %+v, %v", cmd, err)
+	}
+	if cmd, err = modbus.LookupCommand(control, "enable", true, modbus.FuncWriteSingle); err != nil || cmd.Verify {
+		t.Errorf("enable = %+v, %v, want verify turned off", cmd, err)
+	}
+	if _, err := modbus.LookupCommand(control, "mute", false, modbus.FuncWriteCoil, modbus.FuncWriteSingle); err == nil || err.Error() != "control.mute: func_code must be one of 5, 6" {
+		t.Errorf("mute err = %v", err)
+	}
+	if _, err := modbus.LookupCommand(control, "silence", false, modbus.FuncWriteCoil); err == nil || err.Error() != "control not configured: silence" {
+		t.Errorf("silence err = %v", err)
+	}
+	if _, err := modbus.LookupCommand("", "reset", false, modbus.FuncWriteCoil); err == nil {
+		t.Error("LookupCommand accepted empty control")
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	if err := modbus.Confirm(" reset ", "reset"); err != nil {
+		t.Error(err)
+	}
+	err := modbus.Confirm("reset", "isolate:yg0101")
+	if err != modbus.ErrConfirm || strings.Contains(err.Error(), "yg0101") {
+		t.Errorf("err = %v, want ErrConfirm without the expected token", err)
+	}
+}
+
+func TestExecuteVerify(t *testing.T) {
+	slave := modbustest.NewSlave()
+	client := modbus.NewRTUClient(slave)
+
+	res := client.Execute(1, "isolate", modbus.Command{FuncCode: modbus.FuncWriteSingle, Verify: true}, 1257, 1)
+	if res.Err != nil || !res.Accepted || res.Readback == nil || *res.Readback != 1 || !*res.Verified {
+		t.Errorf("FC06 = %+v", res)
+	}
+	if slave.Requests != 2 {
+		t.Errorf("requests = %d, want write + read-back", slave.Requests)
+	}
+
+	// FC05 写入值归一为 0/1，读回线圈
+	res = client.Execute(1, "mute", modbus.Command{FuncCode: modbus.FuncWriteCoil, Verify: true}, 10, 5)
+	if res.Err != nil || res.Value != 1 || *res.Readback != 1 || !slave.Coils[10] {
+		t.Errorf("FC05 = %+v", res)
+	}
+
+	res = client.Execute(1, "shutdown_with_delay", modbus.Command{FuncCode: modbus.FuncWriteMultiple, Verify: true}, 201, 5)
+	if res.Err != nil || *res.Readback != 5 || slave.Holding[201] != 5 {
+		t.Errorf("FC16 = %+v", res)
+	}
+
+	out := res.Output()
+	if out["success"] != true {
+		t.Errorf("output = %v", out)
+	}
+}
+
+func TestExecutePulse(t *testing.T) {
+	slave := modbustest.NewSlave()
+	slave.Pulse = true
+	client := modbus.NewRTUClient(slave)
+
+	// 缺省不读回：自动清零的命令寄存器视为成功
+	res := client.Execute(1, "reset", modbus.Command{FuncCode: modbus.FuncWriteCoil}, 1, 1)
+	if res.Err != nil || !res.Accepted || res.Readback != nil || res.Verified != nil {
+		t.Errorf("reset = %+v", res)
+	}
+	if slave.Requests != 1 {
+		t.Errorf("requests = %d, want write only", slave.Requests)
+	}
+
+	res = client.Execute(1, "battery_test", modbus.Command{FuncCode: modbus.FuncWriteSingle, Verify: true}, 200, 1)
+	if res.Err != modbus.ErrReadbackMismatch || *res.Readback != 0 || *res.Verified {
+		t.Errorf("verify on pulse register = %+v", res)
+	}
+	if out := res.Output(); out["success"] != false || out["error"] != "read-back mismatch" {
+		t.Errorf("output = %v", out)
+	}
+}
+
+func TestExecuteException(t *testing.T) {
+	slave := modbustest.NewSlave()
+	slave.ReadOnly[2] = true
+	client := modbus.NewRTUClient(slave)
+
+	res := client.Execute(1, "silence", modbus.Command{FuncCode: modbus.FuncWriteCoil, Verify: true}, 2, 1)
+	if res.Accepted || res.Exception != modbus.ExceptionIllegalDataAddress || res.Readback != nil {
+		t.Errorf("silence = %+v", res)
+	}
+	if slave.Requests != 1 {
+		t.Errorf("requests = %d, want no read-back after exception", slave.Requests)
+	}
+}
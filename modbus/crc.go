@@ -0,0 +1,31 @@
+package modbus
+
+// CRC16 计算 Modbus RTU 校验码（多项式 0xA001，初值 0xFFFF）
+func CRC16(data []byte) uint16 {
+	var crc uint16 = 0xFFFF
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&0x0001 != 0 {
+				crc = (crc >> 1) ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+// CheckCRC 校验帧尾两字节（低字节在前）
+func CheckCRC(data []byte) bool {
+	if len(data) < 2 {
+		return false
+	}
+	got := uint16(data[len(data)-2]) | uint16(data[len(data)-1])<<8
+	return CRC16(data[:len(data)-2]) == got
+}
+
+func appendCRC(frame []byte) []byte {
+	crc := CRC16(frame)
+	return append(frame, byte(crc), byte(crc>>8))
+}
@@ -0,0 +1,72 @@
+package modbus
+
+// Error 简单字符串错误，避免在 TinyGo 下引入 fmt
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+const (
+	ErrTimeout          = Error("response timeout")
+	ErrInvalidResponse  = Error("invalid response")
+	ErrUnexpectedFunc   = Error("unexpected function code")
+	ErrByteCount        = Error("byte count mismatch")
+	ErrCRC              = Error("crc error")
+	ErrInsufficientData = Error("insufficient register data")
+	ErrWriteMismatch    = Error("write echo mismatch")
+	ErrTransactionID    = Error("mbap transaction id mismatch")
+	ErrProtocolID       = Error("mbap protocol id not 0")
+	ErrMBAPLength       = Error("mbap length mismatch")
+)
+
+// 异常码
+const (
+	ExceptionIllegalFunction         = 0x01 // 非法功能
+	ExceptionIllegalDataAddress      = 0x02 // 非法数据地址
+	ExceptionIllegalDataValue        = 0x03 // 非法数据值
+	ExceptionServerDeviceFailure     = 0x04 // 从站设备故障
+	ExceptionAcknowledge             = 0x05 // 已确认，处理中
+	ExceptionServerDeviceBusy        = 0x06 // 从站设备忙
+	ExceptionNegativeAcknowledge     = 0x07 // 否定确认
+	ExceptionMemoryParityError       = 0x08 // 存储奇偶校验错误
+	ExceptionGatewayPathUnavailable  = 0x0A // 网关路径不可用
+	ExceptionGatewayTargetNoResponse = 0x0B // 网关目标设备无响应
+)
+
+var exceptionNames = map[byte]string{
+	ExceptionIllegalFunction:         "illegal function",
+	ExceptionIllegalDataAddress:      "illegal data address",
+	ExceptionIllegalDataValue:        "illegal data value",
+	ExceptionServerDeviceFailure:     "server device failure",
+	ExceptionAcknowledge:             "acknowledge",
+	ExceptionServerDeviceBusy:        "server device busy",
+	ExceptionNegativeAcknowledge:     "negative acknowledge",
+	ExceptionMemoryParityError:       "memory parity error",
+	ExceptionGatewayPathUnavailable:  "gateway path unavailable",
+	ExceptionGatewayTargetNoResponse: "gateway target device failed to respond",
+}
+
+// ExceptionError 从站返回的异常响应（功能码最高位置 1）
+type ExceptionError struct {
+	FuncCode byte // 请求功能码
+	Code     byte // 异常码
+}
+
+// Name 异常码名称，如 "illegal data address"，未定义的异常码返回 "unknown exception"
+func (e ExceptionError) Name() string {
+	if name, ok := exceptionNames[e.Code]; ok {
+		return name
+	}
+	return "unknown exception"
+}
+
+// Error 形如 "modbus exception 02 illegal data address"
+func (e ExceptionError) Error() string {
+	const hex = "0123456789ABCDEF"
+	return "modbus exception " + string([]byte{hex[e.Code>>4], hex[e.Code&0x0F]}) + " " + e.Name()
+}
+
+// AsException 判断 err 是否为异常响应
+func AsException(err error) (ExceptionError, bool) {
+	e, ok := err.(ExceptionError)
+	return e, ok
+}
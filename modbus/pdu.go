@@ -0,0 +1,76 @@
+package modbus
+
+func readPDU(funcCode byte, start uint16, qty uint16) []byte {
+	return []byte{
+		funcCode,
+		byte(start >> 8), byte(start), // 起始地址
+		byte(qty >> 8), byte(qty), // 寄存器数量
+	}
+}
+
+func writeSinglePDU(reg uint16, value uint16) []byte {
+	return []byte{
+		FuncWriteSingle,
+		byte(reg >> 8), byte(reg),
+		byte(value >> 8), byte(value),
+	}
+}
+
+func writeCoilPDU(addr uint16, on bool) []byte {
+	var v byte
+	if on {
+		v = 0xFF
+	}
+	return []byte{
+		FuncWriteCoil,
+		byte(addr >> 8), byte(addr),
+		v, 0x00,
+	}
+}
+
+func writeMultiplePDU(start uint16, values []uint16) []byte {
+	qty := len(values)
+	pdu := make([]byte, 0, 6+qty*2)
+	pdu = append(pdu,
+		FuncWriteMultiple,
+		byte(start>>8), byte(start),
+		byte(qty>>8), byte(qty),
+		byte(qty*2),
+	)
+	for _, v := range values {
+		pdu = append(pdu, byte(v>>8), byte(v))
+	}
+	return pdu
+}
+
+// checkWriteEcho 比较响应与请求的 功能码+地址+值/数量 共 5 字节
+func checkWriteEcho(got []byte, want []byte) error {
+	for i := range want {
+		if got[i] != want[i] {
+			return ErrWriteMismatch
+		}
+	}
+	return nil
+}
+
+func decodeRegisters(b []byte) []uint16 {
+	values := make([]uint16, len(b)/2)
+	for i := 0; i < len(values); i++ {
+		values[i] = uint16(b[i*2])<<8 | uint16(b[i*2+1])
+	}
+	return values
+}
+
+// decodeCoils 线圈响应按字节低位在前排列
+func decodeCoils(b []byte, count uint16) []bool {
+	bits := make([]bool, count)
+	for i := range bits {
+		bits[i] = b[i/8]>>(uint(i)%8)&1 == 1
+	}
+	return bits
+}
+
+// coilBytes count 个线圈占用的响应字节数
+func coilBytes(count uint16) int {
+	return (int(count) + 7) / 8
+}
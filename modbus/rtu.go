@@ -0,0 +1,107 @@
+package modbus
+
+// BuildRTURead 构建 Modbus RTU 读请求帧（FC01/FC03/FC04）
+func BuildRTURead(addr byte, funcCode byte, start uint16, qty uint16) []byte {
+	return rtuFrame(addr, readPDU(funcCode, start, qty))
+}
+
+// BuildRTUWriteSingle 构建 FC06 写单个寄存器请求帧
+func BuildRTUWriteSingle(addr byte, reg uint16, value uint16) []byte {
+	return rtuFrame(addr, writeSinglePDU(reg, value))
+}
+
+// BuildRTUWriteCoil 构建 FC05 写单个线圈请求帧
+func BuildRTUWriteCoil(addr byte, coil uint16, on bool) []byte {
+	return rtuFrame(addr, writeCoilPDU(coil, on))
+}
+
+// BuildRTUWriteMultiple 构建 FC16 写多个寄存器请求帧
+func BuildRTUWriteMultiple(addr byte, start uint16, values []uint16) []byte {
+	return rtuFrame(addr, writeMultiplePDU(start, values))
+}
+
+// RTUReadRespLen 读响应的期望长度: 地址+功能码+字节数+数据+CRC
+func RTUReadRespLen(qty uint16) int {
+	return int(qty)*2 + 5
+}
+
+// RTUCoilsRespLen FC01 读响应的期望长度
+func RTUCoilsRespLen(qty uint16) int {
+	return coilBytes(qty) + 5
+}
+
+// RTUWriteRespLen 写响应（FC05/FC06/FC16）固定 8 字节
+const RTUWriteRespLen = 8
+
+// ParseRTURead 解析 Modbus RTU 读响应，返回寄存器值
+func ParseRTURead(data []byte, addr byte, funcCode byte) ([]uint16, error) {
+	payload, err := rtuPayload(data, addr, funcCode, 2)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRegisters(payload), nil
+}
+
+// ParseRTUCoils 解析 FC01/FC02 读响应，返回 count 个位
+func ParseRTUCoils(data []byte, addr byte, funcCode byte, count uint16) ([]bool, error) {
+	payload, err := rtuPayload(data, addr, funcCode, coilBytes(count))
+	if err != nil {
+		return nil, err
+	}
+	return decodeCoils(payload, count), nil
+}
+
+// rtuPayload 校验读响应的地址、功能码、字节数与 CRC，返回数据字节
+func rtuPayload(data []byte, addr byte, funcCode byte, minBytes int) ([]byte, error) {
+	if len(data) < 5 || data[0] != addr {
+		return nil, ErrInvalidResponse
+	}
+	if data[1] == (funcCode | 0x80) {
+		return nil, exceptionFromRTU(data, funcCode)
+	}
+	if data[1] != funcCode {
+		return nil, ErrUnexpectedFunc
+	}
+	byteCnt := int(data[2])
+	if byteCnt < minBytes || len(data) < 3+byteCnt+2 {
+		return nil, ErrByteCount
+	}
+	if !CheckCRC(data[:3+byteCnt+2]) {
+		return nil, ErrCRC
+	}
+	return data[3 : 3+byteCnt], nil
+}
+
+// ParseRTUWrite 校验 FC05/FC06/FC16 写响应：从站应回显请求的前 6 字节
+func ParseRTUWrite(data []byte, req []byte) error {
+	if len(data) < 5 || len(req) < 6 || data[0] != req[0] {
+		return ErrInvalidResponse
+	}
+	if data[1] == (req[1] | 0x80) {
+		return exceptionFromRTU(data, req[1])
+	}
+	if data[1] != req[1] {
+		return ErrUnexpectedFunc
+	}
+	if len(data) < RTUWriteRespLen {
+		return ErrByteCount
+	}
+	if !CheckCRC(data[:RTUWriteRespLen]) {
+		return ErrCRC
+	}
+	return checkWriteEcho(data[1:6], req[1:6])
+}
+
+func exceptionFromRTU(data []byte, funcCode byte) error {
+	if !CheckCRC(data[:5]) {
+		return ErrCRC
+	}
+	return ExceptionError{FuncCode: funcCode, Code: data[2]}
+}
+
+func rtuFrame(addr byte, pdu []byte) []byte {
+	frame := make([]byte, 0, len(pdu)+3)
+	frame = append(frame, addr)
+	frame = append(frame, pdu...)
+	return appendCRC(frame)
+}
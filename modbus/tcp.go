@@ -0,0 +1,130 @@
+package modbus
+
+// BuildTCPRead 构建 Modbus TCP 读请求（MBAP 头 + PDU）
+func BuildTCPRead(tid uint16, unit byte, funcCode byte, start uint16, qty uint16) []byte {
+	return tcpFrame(tid, unit, readPDU(funcCode, start, qty))
+}
+
+// BuildTCPWriteSingle 构建 FC06 写单个寄存器请求
+func BuildTCPWriteSingle(tid uint16, unit byte, reg uint16, value uint16) []byte {
+	return tcpFrame(tid, unit, writeSinglePDU(reg, value))
+}
+
+// BuildTCPWriteCoil 构建 FC05 写单个线圈请求
+func BuildTCPWriteCoil(tid uint16, unit byte, coil uint16, on bool) []byte {
+	return tcpFrame(tid, unit, writeCoilPDU(coil, on))
+}
+
+// BuildTCPWriteMultiple 构建 FC16 写多个寄存器请求
+func BuildTCPWriteMultiple(tid uint16, unit byte, start uint16, values []uint16) []byte {
+	return tcpFrame(tid, unit, writeMultiplePDU(start, values))
+}
+
+// TCPReadRespLen 读响应的期望长度: MBAP(7)+功能码+字节数+数据
+func TCPReadRespLen(qty uint16) int {
+	return int(qty)*2 + 9
+}
+
+// TCPCoilsRespLen FC01 读响应的期望长度
+func TCPCoilsRespLen(qty uint16) int {
+	return coilBytes(qty) + 9
+}
+
+// TCPWriteRespLen 写响应（FC05/FC06/FC16）固定 12 字节
+const TCPWriteRespLen = 12
+
+// ParseTCPRead 解析 Modbus TCP 读响应，返回寄存器值
+func ParseTCPRead(data []byte, tid uint16, unit byte, funcCode byte) ([]uint16, error) {
+	payload, err := tcpPayload(data, tid, unit, funcCode)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRegisters(payload), nil
+}
+
+// ParseTCPCoils 解析 FC01/FC02 读响应，返回 count 个位
+func ParseTCPCoils(data []byte, tid uint16, unit byte, funcCode byte, count uint16) ([]bool, error) {
+	payload, err := tcpPayload(data, tid, unit, funcCode)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) < coilBytes(count) {
+		return nil, ErrByteCount
+	}
+	return decodeCoils(payload, count), nil
+}
+
+// tcpPayload 校验读响应的 MBAP 头、功能码与字节数，返回数据字节
+func tcpPayload(data []byte, tid uint16, unit byte, funcCode byte) ([]byte, error) {
+	data, err := checkMBAP(data, tid, unit)
+	if err != nil {
+		return nil, err
+	}
+	pdu := data[7:]
+	if pdu[0] == (funcCode | 0x80) {
+		return nil, ExceptionError{FuncCode: funcCode, Code: pdu[1]}
+	}
+	if pdu[0] != funcCode {
+		return nil, ErrUnexpectedFunc
+	}
+	byteCnt := int(pdu[1])
+	if len(pdu) != 2+byteCnt {
+		return nil, ErrByteCount
+	}
+	return pdu[2 : 2+byteCnt], nil
+}
+
+// ParseTCPWrite 校验 FC05/FC06/FC16 写响应：从站应回显请求 PDU 的前 5 字节
+func ParseTCPWrite(data []byte, req []byte) error {
+	if len(req) < 12 {
+		return ErrInvalidResponse
+	}
+	data, err := checkMBAP(data, uint16(req[0])<<8|uint16(req[1]), req[6])
+	if err != nil {
+		return err
+	}
+	if data[7] == (req[7] | 0x80) {
+		return ExceptionError{FuncCode: req[7], Code: data[8]}
+	}
+	if data[7] != req[7] {
+		return ErrUnexpectedFunc
+	}
+	if len(data) < TCPWriteRespLen {
+		return ErrByteCount
+	}
+	return checkWriteEcho(data[7:12], req[7:12])
+}
+
+// checkMBAP 校验 MBAP 头：事务标识、协议标识、长度与单元标识，
+// 返回按长度字段截取的完整帧（至少含功能码与 1 字节数据）
+func checkMBAP(data []byte, tid uint16, unit byte) ([]byte, error) {
+	if len(data) < 9 {
+		return nil, ErrInvalidResponse
+	}
+	if uint16(data[0])<<8|uint16(data[1]) != tid {
+		return nil, ErrTransactionID
+	}
+	if data[2] != 0 || data[3] != 0 {
+		return nil, ErrProtocolID
+	}
+	length := int(data[4])<<8 | int(data[5])
+	if length < 3 || len(data) < 6+length {
+		return nil, ErrMBAPLength
+	}
+	if data[6] != unit {
+		return nil, ErrInvalidResponse
+	}
+	return data[:6+length], nil
+}
+
+func tcpFrame(tid uint16, unit byte, pdu []byte) []byte {
+	length := len(pdu) + 1
+	frame := make([]byte, 0, 7+len(pdu))
+	frame = append(frame,
+		byte(tid>>8), byte(tid), // 事务标识
+		0x00, 0x00, // 协议标识
+		byte(length>>8), byte(length), // 后续长度
+		unit, // 单元标识
+	)
+	return append(frame, pdu...)
+}
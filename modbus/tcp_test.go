@@ -0,0 +1,65 @@
+package modbus
+
+import "testing"
+
+// mbap 构造响应帧：length 为 MBAP 长度字段，pdu 为功能码起的字节
+func mbap(tid uint16, proto uint16, length int, unit byte, pdu ...byte) []byte {
+	return append([]byte{byte(tid >> 8), byte(tid), byte(proto >> 8), byte(proto), byte(length >> 8), byte(length), unit}, pdu...)
+}
+
+func TestParseTCPRead(t *testing.T) {
+	pdu := []byte{FuncReadHolding, 4, 0x00, 0xE7, 0x01, 0xF4}
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"ok", mbap(7, 0, 7, 1, pdu...), nil},
+		{"trailing bytes after length", append(mbap(7, 0, 7, 1, pdu...), 0xAA, 0xBB), nil},
+		{"transaction id", mbap(8, 0, 7, 1, pdu...), ErrTransactionID},
+		{"protocol id", mbap(7, 1, 7, 1, pdu...), ErrProtocolID},
+		{"length beyond frame", mbap(7, 0, 9, 1, pdu...), ErrMBAPLength},
+		{"length below minimum", mbap(7, 0, 2, 1, pdu...), ErrMBAPLength},
+		{"length cuts data", mbap(7, 0, 6, 1, pdu...), ErrByteCount},
+		{"unit id", mbap(7, 0, 7, 2, pdu...), ErrInvalidResponse},
+		{"short frame", mbap(7, 0, 2, 1, FuncReadHolding), ErrInvalidResponse},
+		{"function code", mbap(7, 0, 7, 1, append([]byte{FuncReadInput}, pdu[1:]...)...), ErrUnexpectedFunc},
+		{"exception", mbap(7, 0, 3, 1, FuncReadHolding|0x80, ExceptionIllegalDataAddress),
+			ExceptionError{FuncCode: FuncReadHolding, Code: ExceptionIllegalDataAddress}},
+	}
+	for _, tt := range tests {
+		values, err := ParseTCPRead(tt.data, 7, 1, FuncReadHolding)
+		if err != tt.want {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+			continue
+		}
+		if err == nil && (len(values) != 2 || values[0] != 231 || values[1] != 500) {
+			t.Errorf("%s: values = %v", tt.name, values)
+		}
+	}
+}
+
+func TestParseTCPWrite(t *testing.T) {
+	req := BuildTCPWriteSingle(3, 1, 200, 1)
+	echo := func(tid uint16, proto uint16, length int, unit byte) []byte {
+		return mbap(tid, proto, length, unit, req[7:]...)
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"ok", echo(3, 0, 6, 1), nil},
+		{"transaction id", echo(4, 0, 6, 1), ErrTransactionID},
+		{"protocol id", echo(3, 0x0100, 6, 1), ErrProtocolID},
+		{"length beyond frame", echo(3, 0, 7, 1), ErrMBAPLength},
+		{"length cuts echo", echo(3, 0, 5, 1), ErrByteCount},
+		{"unit id", echo(3, 0, 6, 9), ErrInvalidResponse},
+		{"echo value", mbap(3, 0, 6, 1, FuncWriteSingle, 0, 200, 0, 2), ErrWriteMismatch},
+	}
+	for _, tt := range tests {
+		if err := ParseTCPWrite(tt.data, req); err != tt.want {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
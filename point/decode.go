@@ -0,0 +1,197 @@
+package point
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 扩展数据类型，寄存器数量见 Count
+const (
+	TypeUint64  = "uint64"
+	TypeInt64   = "int64"
+	TypeFloat32 = "float32" // IEEE 754 单精度
+	TypeFloat64 = "float64" // IEEE 754 双精度
+	TypeBCD16   = "bcd16"   // 4 位 BCD，0~9999
+	TypeBCD32   = "bcd32"   // 8 位 BCD，0~99999999
+	TypeString  = "string"  // 每寄存器 2 个 ASCII 字符，寄存器数由 Length 指定
+)
+
+// 字节序：A 为最高字节。多寄存器类型按此组合，默认 ABCD（高字在前、字内高字节在前）
+const (
+	OrderABCD = "ABCD" // 大端
+	OrderCDAB = "CDAB" // 低字在前，字内大端
+	OrderBADC = "BADC" // 高字在前，字内字节交换
+	OrderDCBA = "DCBA" // 小端
+)
+
+// typeWords 定长数据类型占用的寄存器数
+var typeWords = map[string]uint16{
+	TypeUint16:  1,
+	TypeInt16:   1,
+	TypeBCD16:   1,
+	TypeUint32:  2,
+	TypeInt32:   2,
+	TypeFloat32: 2,
+	TypeBCD32:   2,
+	TypeUint64:  4,
+	TypeInt64:   4,
+	TypeFloat64: 4,
+}
+
+var (
+	errInvalidBCD = errors.New("invalid bcd digit")
+	errNotNumber  = errors.New("not a finite number")
+)
+
+// ValidType 是否为支持的数据类型（空视为 uint16）
+func ValidType(t string) bool {
+	_, ok := typeWords[t]
+	return ok || t == "" || t == TypeString
+}
+
+// ValidOrder 是否为支持的字节序（空视为 ABCD）
+func ValidOrder(o string) bool {
+	switch o {
+	case "", OrderABCD, OrderCDAB, OrderBADC, OrderDCBA:
+		return true
+	}
+	return false
+}
+
+// ByteOrder 返回字节序，缺省 ABCD
+func (p Point) ByteOrder() string {
+	if p.Order == "" {
+		return OrderABCD
+	}
+	return p.Order
+}
+
+// IsText 是否为字符串测点
+func (p Point) IsText() bool {
+	return p.Type() == TypeString
+}
+
+// order 按字节序把寄存器调整为 ABCD 顺序：CDAB/DCBA 反转字序，BADC/DCBA 交换字内字节
+func (p Point) order(words []uint16) []uint16 {
+	o := p.ByteOrder()
+	if o == OrderABCD {
+		return words
+	}
+	out := make([]uint16, len(words))
+	for i, w := range words {
+		j := i
+		if (o == OrderCDAB || o == OrderDCBA) && !p.IsText() {
+			j = len(words) - 1 - i
+		}
+		if o == OrderBADC || o == OrderDCBA {
+			w = w<<8 | w>>8
+		}
+		out[j] = w
+	}
+	return out
+}
+
+// Decode 按数据类型与字节序把寄存器解析为原始值（未缩放），位字段测点取其中的位；
+// 非法 BCD 与 NaN/Inf 返回错误，字符串测点使用 Text
+func (p Point) Decode(words []uint16) (float64, error) {
+	n := p.Count()
+	if len(words) < int(n) {
+		return 0, errNotRead
+	}
+	w := p.order(words[:n])
+	var u uint64
+	for _, x := range w {
+		u = u<<16 | uint64(x)
+	}
+	if p.Bits > 0 {
+		return float64(u >> p.Bit & (uint64(1)<<p.Bits - 1)), nil
+	}
+	switch p.Type() {
+	case TypeInt16:
+		return float64(int16(p.mask(w[0]))), nil
+	case TypeUint32, TypeUint64:
+		return float64(u), nil
+	case TypeInt32:
+		return float64(int32(uint32(u))), nil
+	case TypeInt64:
+		return float64(int64(u)), nil
+	case TypeFloat32:
+		return finite(float64(math.Float32frombits(uint32(u))))
+	case TypeFloat64:
+		return finite(math.Float64frombits(u))
+	case TypeBCD16:
+		return bcd(uint64(p.mask(w[0])), 4)
+	case TypeBCD32:
+		return bcd(u, 8)
+	case TypeString:
+		return 0, errors.New("string point has no numeric value")
+	}
+	return float64(p.mask(w[0])), nil
+}
+
+func finite(f float64) (float64, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, errNotNumber
+	}
+	return f, nil
+}
+
+// bcd 解析 digits 位压缩 BCD（每 4 位一个十进制数字）
+func bcd(u uint64, digits int) (float64, error) {
+	var v uint64
+	for i := digits - 1; i >= 0; i-- {
+		d := u >> (4 * uint(i)) & 0xF
+		if d > 9 {
+			return 0, errInvalidBCD
+		}
+		v = v*10 + d
+	}
+	return float64(v), nil
+}
+
+// CheckBits 检查位字段：只用于整数类型，不与 Mask 同用，且不超出寄存器宽度
+func (p Point) CheckBits() error {
+	if p.Bits == 0 {
+		if p.Bit != 0 {
+			return errors.New("bit needs bits")
+		}
+		return nil
+	}
+	switch p.Type() {
+	case TypeUint16, TypeInt16, TypeUint32, TypeInt32, TypeUint64, TypeInt64:
+	default:
+		return errors.New("bit field needs an integer data type")
+	}
+	if p.Mask != 0 {
+		return errors.New("bit field cannot be combined with bitand mask")
+	}
+	if int(p.Bit)+int(p.Bits) > 16*int(p.Count()) {
+		return errors.New("bit field exceeds " + strconv.Itoa(16*int(p.Count())) + " bits")
+	}
+	return nil
+}
+
+// Text 字符串测点的值：每寄存器高字节在前（BADC/DCBA 交换），去掉首尾空格与 NUL
+func (p Point) Text(words []uint16) string {
+	w := p.order(words)
+	b := make([]byte, 0, 2*len(w))
+	for _, x := range w {
+		b = append(b, byte(x>>8), byte(x))
+	}
+	return strings.Trim(string(b), " \x00")
+}
+
+// OutputText 生成字符串测点的输出条目
+func (p Point) OutputText(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"field_name": p.Field,
+		"value":      text,
+		"rw":         p.RW,
+		"unit":       p.Unit,
+		"label":      p.Label,
+		"quality":    QualityGood,
+		"reason":     "",
+	}
+}
@@ -0,0 +1,84 @@
+package point
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// State 状态量的一个取值及其含义
+type State struct {
+	Value int64  `json:"value"`
+	Label string `json:"label"`
+}
+
+// 常用状态集，点表中可直接写名称（如 配置:states 填 switch）
+var (
+	// StatesSwitch 开关状态（列头柜 170~186 取最高位后）
+	StatesSwitch = []State{{Value: 0, Label: "分闸"}, {Value: 1, Label: "合闸"}}
+	// StatesAlarm 告警/状态位
+	StatesAlarm = []State{{Value: 0, Label: "正常"}, {Value: 1, Label: "告警"}}
+)
+
+// StatePresets 状态集名称，供点表引用
+var StatePresets = map[string][]State{
+	"switch": StatesSwitch,
+	"alarm":  StatesAlarm,
+}
+
+// ParseStates 解析状态定义：状态集名称（见 StatePresets）或 "0=分闸;1=合闸" 形式，
+// 分隔符可用 ; 或 ,，值可写 0x8000
+func ParseStates(s string) ([]State, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if preset, ok := StatePresets[s]; ok {
+		return preset, nil
+	}
+	var states []State
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == ',' || r == '；' || r == '，' }) {
+		eq := strings.IndexByte(item, '=')
+		if eq < 0 {
+			return nil, errors.New("states " + strconv.Quote(item) + ": want value=label")
+		}
+		v, err := strconv.ParseInt(strings.TrimSpace(item[:eq]), 0, 64)
+		if err != nil {
+			return nil, errors.New("states " + strconv.Quote(item) + ": invalid value")
+		}
+		states = append(states, State{Value: v, Label: strings.TrimSpace(item[eq+1:])})
+	}
+	return states, checkStates(states)
+}
+
+// checkStates 状态集非空，标签非空且取值不重复
+func checkStates(states []State) error {
+	if len(states) == 0 {
+		return errors.New("states: empty")
+	}
+	for i, st := range states {
+		if st.Label == "" {
+			return errors.New("states: empty label for " + strconv.FormatInt(st.Value, 10))
+		}
+		for _, prev := range states[:i] {
+			if prev.Value == st.Value {
+				return errors.New("states: duplicate value " + strconv.FormatInt(st.Value, 10))
+			}
+		}
+	}
+	return nil
+}
+
+// StateLabel 工程值对应的状态含义，未定义的取值返回 false
+func (p Point) StateLabel(value float64) (string, bool) {
+	if value != math.Trunc(value) {
+		return "", false
+	}
+	for _, st := range p.States {
+		if st.Value == int64(value) {
+			return st.Label, true
+		}
+	}
+	return "", false
+}
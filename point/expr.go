@@ -0,0 +1,425 @@
+package point
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Expr 编译后的换算表达式，在采集时对原始值 v 求值。
+//
+// 语法（优先级由低到高）：
+//
+//	c ? a : b                     条件
+//	||  &&                        逻辑（非 0 为真，结果 1/0）
+//	==  !=  <  <=  >  >=          比较
+//	|  ^  &                       按位或 / 异或 / 与（按 int64 运算）
+//	<<  >>                        移位
+//	+  -  *  /  %                 算术
+//	-x  !x  (x)                   一元与括号
+//
+// 函数：bitand(a,b)、bitor(a,b)、bitxor(a,b)、shl(a,n)、shr(a,n)、
+// min(a,b,…)、max(a,b,…)、abs(a)、if(c,a,b)。
+// v 为本测点的原始值（按 DataType 解析后、未缩放），其他标识符为同一点表中
+// 其他测点的工程值，如 (v-Ua_zero)*CT。
+type Expr struct {
+	root *exprNode
+	refs []string
+}
+
+// exprNode 语法树节点：op 为 0 时是常量，'v' 为原始值，'$' 为测点引用，
+// 其余为运算符或函数
+type exprNode struct {
+	op   string
+	num  float64
+	name string
+	args []*exprNode
+}
+
+// CompileExpr 编译表达式
+func CompileExpr(src string) (*Expr, error) {
+	p := &exprParser{src: strings.ReplaceAll(src, " ", "")}
+	root, err := p.parseCond()
+	if err == nil && p.pos != len(p.src) {
+		err = errors.New("unexpected " + strconv.Quote(p.src[p.pos:]))
+	}
+	if err != nil {
+		return nil, errors.New("expression " + strconv.Quote(src) + ": " + err.Error())
+	}
+	return &Expr{root: root, refs: p.refs}, nil
+}
+
+// Refs 表达式引用的其他测点字段名
+func (e *Expr) Refs() []string {
+	return e.refs
+}
+
+// Eval 以原始值 v 求值，ref 返回被引用测点的工程值
+func (e *Expr) Eval(v float64, ref func(field string) (float64, error)) (float64, error) {
+	return e.root.eval(v, ref)
+}
+
+func (n *exprNode) eval(v float64, ref func(string) (float64, error)) (float64, error) {
+	switch n.op {
+	case "":
+		return n.num, nil
+	case "v":
+		return v, nil
+	case "$":
+		return ref(n.name)
+	case "?":
+		c, err := n.args[0].eval(v, ref)
+		if err != nil {
+			return 0, err
+		}
+		if c != 0 {
+			return n.args[1].eval(v, ref)
+		}
+		return n.args[2].eval(v, ref)
+	case "&&", "||":
+		a, err := n.args[0].eval(v, ref)
+		if err != nil || (n.op == "&&") == (a == 0) {
+			return truth(a != 0), err
+		}
+		b, err := n.args[1].eval(v, ref)
+		return truth(b != 0), err
+	}
+
+	args := make([]float64, len(n.args))
+	for i, a := range n.args {
+		x, err := a.eval(v, ref)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = x
+	}
+	switch n.op {
+	case "neg":
+		return -args[0], nil
+	case "!":
+		return truth(args[0] == 0), nil
+	case "+":
+		return args[0] + args[1], nil
+	case "-":
+		return args[0] - args[1], nil
+	case "*":
+		return args[0] * args[1], nil
+	case "/":
+		return args[0] / args[1], nil
+	case "%":
+		return math.Mod(args[0], args[1]), nil
+	case "==":
+		return truth(args[0] == args[1]), nil
+	case "!=":
+		return truth(args[0] != args[1]), nil
+	case "<":
+		return truth(args[0] < args[1]), nil
+	case "<=":
+		return truth(args[0] <= args[1]), nil
+	case ">":
+		return truth(args[0] > args[1]), nil
+	case ">=":
+		return truth(args[0] >= args[1]), nil
+	case "&", "bitand":
+		return float64(int64(args[0]) & int64(args[1])), nil
+	case "|", "bitor":
+		return float64(int64(args[0]) | int64(args[1])), nil
+	case "^", "bitxor":
+		return float64(int64(args[0]) ^ int64(args[1])), nil
+	case "<<", "shl":
+		return float64(int64(args[0]) << uint64(int64(args[1])&63)), nil
+	case ">>", "shr":
+		return float64(int64(args[0]) >> uint64(int64(args[1])&63)), nil
+	case "abs":
+		return math.Abs(args[0]), nil
+	case "if":
+		if args[0] != 0 {
+			return args[1], nil
+		}
+		return args[2], nil
+	case "min", "max":
+		r := args[0]
+		for _, x := range args[1:] {
+			if (n.op == "min") == (x < r) {
+				r = x
+			}
+		}
+		return r, nil
+	}
+	return 0, errors.New("unknown operator " + n.op)
+}
+
+func truth(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// exprFuncs 函数名与参数个数，-1 表示至少 1 个
+var exprFuncs = map[string]int{
+	"bitand": 2,
+	"bitor":  2,
+	"bitxor": 2,
+	"shl":    2,
+	"shr":    2,
+	"abs":    1,
+	"if":     3,
+	"min":    -1,
+	"max":    -1,
+}
+
+// exprLevels 二元运算符按优先级由低到高，同级内长运算符在前以免 <= 被读成 <
+var exprLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<=", ">=", "<", ">"},
+	{"|"},
+	{"^"},
+	{"&"},
+	{"<<", ">>"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+type exprParser struct {
+	src  string
+	pos  int
+	refs []string
+}
+
+func (p *exprParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *exprParser) parseCond() (*exprNode, error) {
+	c, err := p.parseBinary(0)
+	if err != nil || p.peek() != '?' {
+		return c, err
+	}
+	p.pos++
+	a, err := p.parseCond()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != ':' {
+		return nil, errors.New("missing :")
+	}
+	p.pos++
+	b, err := p.parseCond()
+	if err != nil {
+		return nil, err
+	}
+	return &exprNode{op: "?", args: []*exprNode{c, a, b}}, nil
+}
+
+func (p *exprParser) parseBinary(level int) (*exprNode, error) {
+	if level == len(exprLevels) {
+		return p.parseUnary()
+	}
+	l, err := p.parseBinary(level + 1)
+	for err == nil {
+		op := p.operator(exprLevels[level])
+		if op == "" {
+			break
+		}
+		var r *exprNode
+		if r, err = p.parseBinary(level + 1); err == nil {
+			l = &exprNode{op: op, args: []*exprNode{l, r}}
+		}
+	}
+	return l, err
+}
+
+// operator 匹配当前位置的运算符，不把 && / || 拆成 & / |
+func (p *exprParser) operator(ops []string) string {
+	rest := p.src[p.pos:]
+	for _, op := range ops {
+		if !strings.HasPrefix(rest, op) {
+			continue
+		}
+		if (op == "&" || op == "|") && len(rest) > 1 && rest[1] == op[0] {
+			continue
+		}
+		if (op == "<" || op == ">") && len(rest) > 1 && (rest[1] == op[0] || rest[1] == '=') {
+			continue
+		}
+		p.pos += len(op)
+		return op
+	}
+	return ""
+}
+
+func (p *exprParser) parseUnary() (*exprNode, error) {
+	switch p.peek() {
+	case '-':
+		p.pos++
+		x, err := p.parseUnary()
+		return &exprNode{op: "neg", args: []*exprNode{x}}, err
+	case '!':
+		p.pos++
+		x, err := p.parseUnary()
+		return &exprNode{op: "!", args: []*exprNode{x}}, err
+	}
+	return p.parseAtom()
+}
+
+func (p *exprParser) parseAtom() (*exprNode, error) {
+	switch ch := p.peek(); {
+	case ch == '(':
+		p.pos++
+		x, err := p.parseCond()
+		if err == nil && p.peek() != ')' {
+			err = errors.New("missing )")
+		}
+		p.pos++
+		return x, err
+	case ch >= '0' && ch <= '9' || ch == '.':
+		return p.number()
+	case isIdentByte(ch):
+		name := p.ident()
+		if name == "v" {
+			return &exprNode{op: "v"}, nil
+		}
+		if p.peek() != '(' {
+			p.refs = append(p.refs, name)
+			return &exprNode{op: "$", name: name}, nil
+		}
+		return p.call(name)
+	case ch == 0:
+		return nil, errors.New("unexpected end")
+	default:
+		return nil, errors.New("unexpected " + strconv.Quote(string(ch)))
+	}
+}
+
+// number 十进制数（可带小数与指数，如 1e-3），或 0x 开头的十六进制整数（如掩码
+// 0x8000）；与 ParseLinear 一致，前导 0 不表示八进制
+func (p *exprParser) number() (*exprNode, error) {
+	start := p.pos
+	hex := strings.HasPrefix(p.src[start:], "0x") || strings.HasPrefix(p.src[start:], "0X")
+	if hex {
+		p.pos += 2
+	}
+	for p.pos < len(p.src) {
+		ch := p.src[p.pos]
+		if !hex && (ch == 'e' || ch == 'E') && p.pos+1 < len(p.src) && (p.src[p.pos+1] == '+' || p.src[p.pos+1] == '-') {
+			p.pos += 2
+			continue
+		}
+		if !isIdentByte(ch) && !(ch >= '0' && ch <= '9') && ch != '.' {
+			break
+		}
+		p.pos++
+	}
+	text := p.src[start:p.pos]
+	if hex {
+		if n, err := strconv.ParseUint(text[2:], 16, 64); err == nil {
+			return &exprNode{num: float64(n)}, nil
+		}
+	} else if n, err := strconv.ParseFloat(text, 64); err == nil {
+		return &exprNode{num: n}, nil
+	}
+	return nil, errors.New("invalid number " + strconv.Quote(text))
+}
+
+func (p *exprParser) call(name string) (*exprNode, error) {
+	want, ok := exprFuncs[name]
+	if !ok {
+		return nil, errors.New("unknown function " + strconv.Quote(name))
+	}
+	p.pos++ // (
+	n := &exprNode{op: name}
+	for {
+		x, err := p.parseCond()
+		if err != nil {
+			return nil, err
+		}
+		n.args = append(n.args, x)
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	if p.peek() != ')' {
+		return nil, errors.New("missing ) after " + name)
+	}
+	p.pos++
+	if want >= 0 && len(n.args) != want {
+		return nil, errors.New(name + " needs " + strconv.Itoa(want) + " arguments")
+	}
+	return n, nil
+}
+
+func (p *exprParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.src) && (isIdentByte(p.src[p.pos]) || p.src[p.pos] >= '0' && p.src[p.pos] <= '9') {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// exprCache 按源码缓存编译结果，采集时不重复解析
+var exprCache = map[string]*Expr{}
+
+// compiled 返回 Expr 的编译结果
+func (p Point) compiled() (*Expr, error) {
+	if e, ok := exprCache[p.Expr]; ok {
+		return e, nil
+	}
+	e, err := CompileExpr(p.Expr)
+	if err != nil {
+		return nil, err
+	}
+	exprCache[p.Expr] = e
+	return e, nil
+}
+
+// CheckRefs 检查按表达式求值的测点：引用的字段须存在且为数值测点，且不得循环引用
+func CheckRefs(points []Point) error {
+	index := map[string]int{}
+	for i, p := range points {
+		index[p.Field] = i
+	}
+	state := make([]byte, len(points)) // 0 未访问，1 访问中，2 完成
+	var visit func(i int) error
+	visit = func(i int) error {
+		p := points[i]
+		if state[i] == 2 || !p.Eval {
+			return nil
+		}
+		if state[i] == 1 {
+			return errors.New(p.Field + ": circular reference")
+		}
+		state[i] = 1
+		e, err := p.compiled()
+		if err != nil {
+			return errors.New(p.Field + ": " + err.Error())
+		}
+		for _, name := range e.Refs() {
+			j, ok := index[name]
+			if !ok {
+				return errors.New(p.Field + ": unknown point " + strconv.Quote(name))
+			}
+			if points[j].IsText() {
+				return errors.New(p.Field + ": " + name + " is not numeric")
+			}
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		state[i] = 2
+		return nil
+	}
+	for i := range points {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package point
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func evalExpr(t *testing.T, src string, v float64, refs map[string]float64) float64 {
+	t.Helper()
+	e, err := CompileExpr(src)
+	if err != nil {
+		t.Fatalf("CompileExpr(%q): %v", src, err)
+	}
+	got, err := e.Eval(v, func(field string) (float64, error) {
+		x, ok := refs[field]
+		if !ok {
+			return 0, errors.New("unknown point " + field)
+		}
+		return x, nil
+	})
+	if err != nil {
+		t.Fatalf("Eval(%q): %v", src, err)
+	}
+	return got
+}
+
+func TestExprEval(t *testing.T) {
+	refs := map[string]float64{"CT": 40, "Ua_zero": 2}
+	tests := []struct {
+		src  string
+		v    float64
+		want float64
+	}{
+		// 数字字面量
+		{"v/010", 80, 8},
+		{"1e-3*v", 2500, 2.5},
+		{"v*2.5E+1", 2, 50},
+		{".5*v", 4, 2},
+		{"v&0x8000", 0x8001, 0x8000},
+		{"v&0XFF", 0x1234, 0x34},
+
+		// 优先级与结合性
+		{"1+2*3", 0, 7},
+		{"(1+2)*3", 0, 9},
+		{"10-4-3", 0, 3},
+		{"64/4/2", 0, 8},
+		{"-v*2", 3, -6},
+		{"2*-v", 3, -6},
+		{"v%7", 23, 2},
+		{"1+2==3", 0, 1},
+		{"1<2==1", 0, 1},
+		{"1|2&3", 0, 3},
+		{"6^3&1", 0, 7},
+		{"1<<2+1", 0, 8},
+		{"v>>8&0xFF", 0x1234, 0x12},
+		{"0||2&&3", 0, 1},
+		{"1&&0||0", 0, 0},
+		{"!v", 0, 1},
+		{"!!v", 5, 1},
+		{"v>100?1:v<10?-1:0", 5, -1},
+		{"v>100?1:v<10?-1:0", 50, 0},
+		{"v<=10", 10, 1},
+		{"v>=11", 10, 0},
+		{"v!=10", 10, 0},
+
+		// 函数
+		{"bitand(v,0x0F)", 0x3C, 0x0C},
+		{"bitor(v,1)", 4, 5},
+		{"bitxor(v,0xFF)", 0x0F, 0xF0},
+		{"shl(v,4)", 1, 16},
+		{"shr(v,4)", 0x80, 8},
+		{"shr(v,1)&1", 2, 1},
+		{"min(v,3,8)", 5, 3},
+		{"max(v,3,8)", 5, 8},
+		{"min(v)", 5, 5},
+		{"abs(v-10)", 3, 7},
+		{"if(v>0,v,-v)", -4, 4},
+
+		// 引用其他测点
+		{"(v-Ua_zero)*CT", 12, 400},
+		{"v * CT / 1000", 500, 20},
+	}
+	for _, tt := range tests {
+		if got := evalExpr(t, tt.src, tt.v, refs); got != tt.want {
+			t.Errorf("%s with v=%v = %v, want %v", tt.src, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestExprShortCircuit(t *testing.T) {
+	// 右侧不求值，不会因引用未知测点而失败
+	if got := evalExpr(t, "0&&missing", 0, nil); got != 0 {
+		t.Errorf("0&&missing = %v", got)
+	}
+	if got := evalExpr(t, "1||missing", 0, nil); got != 1 {
+		t.Errorf("1||missing = %v", got)
+	}
+	if got := evalExpr(t, "v?1:missing", 1, nil); got != 1 {
+		t.Errorf("v?1:missing = %v", got)
+	}
+}
+
+func TestExprDivisionByZero(t *testing.T) {
+	if got := evalExpr(t, "1/v", 0, nil); !math.IsInf(got, 1) {
+		t.Errorf("1/0 = %v, want +Inf", got)
+	}
+	if got := evalExpr(t, "v%0", 3, nil); !math.IsNaN(got) {
+		t.Errorf("3%%0 = %v, want NaN", got)
+	}
+	// 采集时非有限结果作为错误返回
+	p := Point{Field: "X", Expr: "100/v", Eval: true}
+	if _, err := p.Compute([]uint16{0}, nil); err == nil {
+		t.Error("Compute 100/0: want error")
+	}
+	if v, err := p.Compute([]uint16{4}, nil); err != nil || v != 25 {
+		t.Errorf("Compute 100/4 = %v, %v", v, err)
+	}
+}
+
+func TestExprRefs(t *testing.T) {
+	e, err := CompileExpr("(v-Ua_zero)*CT+max(Ib,0)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(e.Refs(), ","); got != "Ua_zero,CT,Ib" {
+		t.Errorf("Refs = %s", got)
+	}
+	if _, err := e.Eval(1, func(field string) (float64, error) {
+		return 0, errors.New("unknown point " + field)
+	}); err == nil || !strings.Contains(err.Error(), "Ua_zero") {
+		t.Errorf("Eval with failing ref: err = %v", err)
+	}
+}
+
+func TestExprCompileErrors(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{"", "unexpected end"},
+		{"v+", "unexpected end"},
+		{"(v+1", "missing )"},
+		{"v)", `unexpected ")"`},
+		{"v?1", "missing :"},
+		{"v#2", `unexpected "#2"`},
+		{"*v", `unexpected "*"`},
+		{"1e", `invalid number "1e"`},
+		{"1e-", `invalid number "1e-"`},
+		{"2v", `invalid number "2v"`},
+		{"1.2.3", `invalid number "1.2.3"`},
+		{"0x", `invalid number "0x"`},
+		{"0xG1", `invalid number "0xG1"`},
+		{"sqrt(v)", `unknown function "sqrt"`},
+		{"abs(v,1)", "abs needs 1 arguments"},
+		{"if(v,1)", "if needs 3 arguments"},
+		{"min(v,1", "missing ) after min"},
+		{"min()", "unexpected"},
+	}
+	for _, tt := range tests {
+		_, err := CompileExpr(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("CompileExpr(%q) err = %v, want %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestCheckRefs(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []Point
+		want   string
+	}{
+		{"ok", []Point{{Field: "CT"}, {Field: "I", Expr: "v*CT", Eval: true}}, ""},
+		{"unknown", []Point{{Field: "I", Expr: "v*CT", Eval: true}}, `I: unknown point "CT"`},
+		{"text", []Point{{Field: "CT", DataType: TypeString, Length: 2}, {Field: "I", Expr: "v*CT", Eval: true}}, "CT is not numeric"},
+		{"circular", []Point{{Field: "A", Expr: "B+v", Eval: true}, {Field: "B", Expr: "A+v", Eval: true}}, "circular reference"},
+		{"bad expr", []Point{{Field: "A", Expr: "v+", Eval: true}}, "A: expression"},
+	}
+	for _, tt := range tests {
+		err := CheckRefs(tt.points)
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
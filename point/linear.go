@@ -0,0 +1,181 @@
+package point
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ParseLinear 把点表表达式化简为 Scale/Offset/Mask，结果须对 v 线性。
+//
+// 支持 v、v/10、v*0.1、v/10-40、(v-101665)/9800 及其组合；空表达式视为 v。
+// bitand(v,N) 只在构成整个表达式时化简为 Mask（Scale 1、Offset 0），与其他
+// 运算组合（如 v+bitand(v,1)）时不是线性换算，返回错误，由调用方按表达式求值。
+func ParseLinear(expr string) (scale, offset float64, mask uint16, err error) {
+	p := &linearParser{src: strings.ReplaceAll(expr, " ", "")}
+	if p.src == "" {
+		p.src = "v"
+	}
+	if strings.HasPrefix(p.src, "bitand(") {
+		p.pos = len("bitand")
+		mask, err = p.bitand()
+		if err == nil && p.pos != len(p.src) {
+			err = errors.New("bitand(v,N) must be the whole expression")
+		}
+		if err != nil {
+			return 0, 0, 0, errors.New("expression " + strconv.Quote(expr) + ": " + err.Error())
+		}
+		return 1, 0, mask, nil
+	}
+	l, err := p.parseSum()
+	if err == nil && p.pos != len(p.src) {
+		err = errors.New("unexpected " + strconv.Quote(p.src[p.pos:]))
+	}
+	if err == nil && l.a == 0 {
+		err = errors.New("does not use v")
+	}
+	if err != nil {
+		return 0, 0, 0, errors.New("expression " + strconv.Quote(expr) + ": " + err.Error())
+	}
+	return l.a, l.b, 0, nil
+}
+
+// linear 表示 a*v + b
+type linear struct {
+	a, b float64
+}
+
+type linearParser struct {
+	src string
+	pos int
+}
+
+func (p *linearParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *linearParser) parseSum() (linear, error) {
+	l, err := p.parseProduct()
+	for err == nil && (p.peek() == '+' || p.peek() == '-') {
+		op := p.src[p.pos]
+		p.pos++
+		var r linear
+		if r, err = p.parseProduct(); err != nil {
+			break
+		}
+		if op == '+' {
+			l = linear{l.a + r.a, l.b + r.b}
+		} else {
+			l = linear{l.a - r.a, l.b - r.b}
+		}
+	}
+	return l, err
+}
+
+func (p *linearParser) parseProduct() (linear, error) {
+	l, err := p.parseUnary()
+	for err == nil && (p.peek() == '*' || p.peek() == '/') {
+		op := p.src[p.pos]
+		p.pos++
+		var r linear
+		if r, err = p.parseUnary(); err != nil {
+			break
+		}
+		switch {
+		case op == '/' && r.a != 0:
+			err = errors.New("division by v is not linear")
+		case op == '/' && r.b == 0:
+			err = errors.New("division by zero")
+		case op == '/':
+			l = linear{l.a / r.b, l.b / r.b}
+		case l.a == 0:
+			l = linear{r.a * l.b, r.b * l.b}
+		case r.a == 0:
+			l = linear{l.a * r.b, l.b * r.b}
+		default:
+			err = errors.New("v*v is not linear")
+		}
+	}
+	return l, err
+}
+
+func (p *linearParser) parseUnary() (linear, error) {
+	if p.peek() == '-' {
+		p.pos++
+		l, err := p.parseUnary()
+		return linear{-l.a, -l.b}, err
+	}
+	return p.parseAtom()
+}
+
+func (p *linearParser) parseAtom() (linear, error) {
+	switch ch := p.peek(); {
+	case ch == '(':
+		p.pos++
+		l, err := p.parseSum()
+		if err == nil && p.peek() != ')' {
+			err = errors.New("missing )")
+		}
+		p.pos++
+		return l, err
+	case ch >= '0' && ch <= '9' || ch == '.':
+		n, err := p.number()
+		return linear{0, n}, err
+	case isIdentByte(ch):
+		switch name := p.ident(); name {
+		case "v":
+			return linear{1, 0}, nil
+		case "bitand":
+			return linear{}, errors.New("bitand(v,N) must be the whole expression")
+		default:
+			return linear{}, errors.New("unsupported identifier " + strconv.Quote(name))
+		}
+	case ch == 0:
+		return linear{}, errors.New("unexpected end")
+	default:
+		return linear{}, errors.New("unexpected " + strconv.Quote(string(ch)))
+	}
+}
+
+// bitand(v,N)：只允许作用于 v 本身，对应 Point.Mask
+func (p *linearParser) bitand() (uint16, error) {
+	if !strings.HasPrefix(p.src[p.pos:], "(v,") {
+		return 0, errors.New("bitand must be bitand(v,N)")
+	}
+	p.pos += 3
+	n, err := p.number()
+	if err != nil {
+		return 0, err
+	}
+	if p.peek() != ')' {
+		return 0, errors.New("missing )")
+	}
+	p.pos++
+	if n <= 0 || n > 0xFFFF || n != float64(uint16(n)) {
+		return 0, errors.New("bitand mask out of range")
+	}
+	return uint16(n), nil
+}
+
+func isIdentByte(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
+
+func (p *linearParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.src) && (isIdentByte(p.src[p.pos]) || p.src[p.pos] >= '0' && p.src[p.pos] <= '9') {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *linearParser) number() (float64, error) {
+	start := p.pos
+	for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+		p.pos++
+	}
+	return strconv.ParseFloat(p.src[start:p.pos], 64)
+}
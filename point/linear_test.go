@@ -0,0 +1,33 @@
+package point
+
+import "testing"
+
+func TestParseLinear(t *testing.T) {
+	tests := []struct {
+		expr          string
+		scale, offset float64
+		mask          uint16
+	}{
+		{"", 1, 0, 0},
+		{"v/10-40", 0.1, -40, 0},
+		{"(v-100)/4", 0.25, -25, 0},
+		{"bitand(v,32768)", 1, 0, 0x8000},
+		{" bitand( v, 255 ) ", 1, 0, 0xFF},
+	}
+	for _, tt := range tests {
+		scale, offset, mask, err := ParseLinear(tt.expr)
+		if err != nil || scale != tt.scale || offset != tt.offset || mask != tt.mask {
+			t.Errorf("ParseLinear(%q) = %v, %v, %#x, %v; want %v, %v, %#x",
+				tt.expr, scale, offset, mask, err, tt.scale, tt.offset, tt.mask)
+		}
+	}
+}
+
+// bitand 与其他运算组合时不能化简为一个全局 Mask
+func TestParseLinearRejectsCombinedBitand(t *testing.T) {
+	for _, expr := range []string{"v+bitand(v,1)", "bitand(v,255)/10", "2*bitand(v,1)", "v*v", "bitand(v,0)"} {
+		if _, _, _, err := ParseLinear(expr); err == nil {
+			t.Errorf("ParseLinear(%q) accepted", expr)
+		}
+	}
+}
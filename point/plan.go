@@ -0,0 +1,56 @@
+package point
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+)
+
+// Plan 生成读取计划：按功能码分组、按地址排序，把相邻或重叠的测点合并为
+// 读请求。间隔不超过 maxGap 个寄存器的测点也合并（连同中间未定义的寄存器
+// 一起读取），单个请求不超过 maxCount 个寄存器；寄存器数超过 maxCount 的测点
+// （长字符串）拆成连续的多个请求，Registers 按地址拼回。
+//
+// 逐点贪心扩展当前请求，得到的请求数最少。maxGap=0 只合并连续地址；
+// 设备对未定义地址返回异常时不要放大 maxGap。maxCount 须为 1~125。
+func Plan(points []Point, maxCount, maxGap uint16) ([]modbus.Block, error) {
+	if maxCount == 0 || maxCount > modbus.MaxReadCount {
+		return nil, errors.New("plan: max registers " + strconv.Itoa(int(maxCount)) + " outside 1~125")
+	}
+	sorted := make([]Point, len(points))
+	copy(sorted, points)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].FuncCode != sorted[j].FuncCode {
+			return sorted[i].FuncCode < sorted[j].FuncCode
+		}
+		return sorted[i].Address < sorted[j].Address
+	})
+
+	var blocks []modbus.Block
+	for _, p := range sorted {
+		end := uint32(p.Address) + uint32(p.Count())
+		if n := len(blocks); n > 0 {
+			last := &blocks[n-1]
+			lastEnd := uint32(last.Start) + uint32(last.Count)
+			if last.FuncCode == p.FuncCode && uint32(p.Address) <= lastEnd+uint32(maxGap) {
+				if end <= lastEnd {
+					continue
+				}
+				if end-uint32(last.Start) <= uint32(maxCount) {
+					last.Count = uint16(end - uint32(last.Start))
+					continue
+				}
+			}
+		}
+		for start := uint32(p.Address); start < end; start += uint32(maxCount) {
+			n := end - start
+			if n > uint32(maxCount) {
+				n = uint32(maxCount)
+			}
+			blocks = append(blocks, modbus.Block{FuncCode: p.FuncCode, Start: uint16(start), Count: uint16(n)})
+		}
+	}
+	return blocks, nil
+}
@@ -0,0 +1,100 @@
+package point
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+)
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		points   []Point
+		maxCount uint16
+		maxGap   uint16
+		want     []modbus.Block
+	}{
+		{
+			name:     "contiguous",
+			points:   []Point{{FuncCode: 3, Address: 1}, {FuncCode: 3, Address: 0}, {FuncCode: 3, Address: 2, DataType: TypeUint32}},
+			maxCount: 125,
+			want:     []modbus.Block{{FuncCode: 3, Start: 0, Count: 4}},
+		},
+		{
+			name:     "gap bridged",
+			points:   []Point{{FuncCode: 3, Address: 0}, {FuncCode: 3, Address: 3}, {FuncCode: 3, Address: 10}},
+			maxCount: 125,
+			maxGap:   2,
+			want:     []modbus.Block{{FuncCode: 3, Start: 0, Count: 4}, {FuncCode: 3, Start: 10, Count: 1}},
+		},
+		{
+			name:     "split by func code and max count",
+			points:   []Point{{FuncCode: 4, Address: 0}, {FuncCode: 3, Address: 0}, {FuncCode: 3, Address: 1}, {FuncCode: 3, Address: 2}},
+			maxCount: 2,
+			want:     []modbus.Block{{FuncCode: 3, Start: 0, Count: 2}, {FuncCode: 3, Start: 2, Count: 1}, {FuncCode: 4, Start: 0, Count: 1}},
+		},
+		{
+			name:     "point longer than max count",
+			points:   []Point{{FuncCode: 3, Address: 0}, {FuncCode: 3, Address: 10, DataType: TypeString, Length: 5}, {FuncCode: 3, Address: 15}},
+			maxCount: 2,
+			want: []modbus.Block{
+				{FuncCode: 3, Start: 0, Count: 1},
+				{FuncCode: 3, Start: 10, Count: 2},
+				{FuncCode: 3, Start: 12, Count: 2},
+				{FuncCode: 3, Start: 14, Count: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Plan(tt.points, tt.maxCount, tt.maxGap)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Plan = %+v, want %+v", got, tt.want)
+			}
+			for _, b := range got {
+				if b.Count > tt.maxCount {
+					t.Errorf("block %+v exceeds %d registers", b, tt.maxCount)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanMaxCount(t *testing.T) {
+	points := []Point{{FuncCode: 3, Address: 0}, {FuncCode: 3, Address: 200}}
+	for _, maxCount := range []uint16{0, 126} {
+		if blocks, err := Plan(points, maxCount, 0); err == nil {
+			t.Errorf("Plan(maxCount=%d) = %+v, want error", maxCount, blocks)
+		}
+	}
+	if _, err := Plan(points, 125, 0); err != nil {
+		t.Errorf("Plan(maxCount=125): %v", err)
+	}
+}
+
+func TestPlanSplitPointReassembled(t *testing.T) {
+	p := Point{Field: "MODEL", FuncCode: 3, Address: 10, DataType: TypeString, Length: 3}
+	var regs Registers
+	blocks, err := Plan([]Point{p}, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range blocks {
+		values := make([]uint16, b.Count)
+		for i := range values {
+			values[i] = uint16('A'+int(b.Start-10)+i)<<8 | ' '
+		}
+		regs.Put(b.FuncCode, b.Start, values)
+	}
+	words, err := regs.Words(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Text(words); got != "A B C" {
+		t.Errorf("Text = %q, want %q", got, "A B C")
+	}
+}
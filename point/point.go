@@ -0,0 +1,208 @@
+// Package point 定义驱动共用的测点模型：点表条目、寄存器取值、工程值换算
+// 以及 describe 导出的机器可读点表。
+package point
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+// 数据类型
+const (
+	TypeUint16 = "uint16"
+	TypeInt16  = "int16"
+	TypeUint32 = "uint32"
+	TypeInt32  = "int32"
+)
+
+// Point 点表条目，工程值 = 原始值 * Scale + Offset
+type Point struct {
+	Field    string  // 字段名
+	Label    string  // 显示标签
+	Unit     string  // 单位
+	RW       string  // 读写属性 "R" | "RW"
+	FuncCode byte    // 读功能码，0 表示由驱动决定
+	Address  uint16  // 寄存器地址
+	Length   uint16  // 寄存器数量，0 按数据类型推断
+	DataType string  // 数据类型，空为 uint16
+	Order    string  // 多寄存器字节序 ABCD | CDAB | BADC | DCBA，空为 ABCD
+	Mask     uint16  // 非 0 时先与寄存器值按位与（如 bitand(v,32768)），仅 16 位类型
+	Bit      uint8   // 位字段起始位（0 为最低位），Bits 为 0 时不使用
+	Bits     uint8   // 位字段宽度，非 0 时取 (原始值 >> Bit) 的低 Bits 位；同一寄存器可拆出多个测点
+	Scale    float64 // 缩放系数，0 视为 1
+	Offset   float64 // 偏移量
+	Expr     string  // 原始表达式；Eval 为 false 时仅用于描述
+	Eval     bool    // 按 Expr 求值（非线性或引用其他测点），此时不使用 Mask/Scale/Offset
+	Decimals int     // 有效小数位数
+	States   []State // 状态量取值与含义，非空时输出 state
+	Min      float64 // 有效范围下限，Min/Max 均为 0 时按数据类型推导
+	Max      float64 // 有效范围上限
+}
+
+// Type 返回数据类型，缺省 uint16
+func (p Point) Type() string {
+	if p.DataType == "" {
+		return TypeUint16
+	}
+	return p.DataType
+}
+
+// Count 返回占用的寄存器数量
+func (p Point) Count() uint16 {
+	if p.Length > 0 {
+		return p.Length
+	}
+	if n, ok := typeWords[p.Type()]; ok {
+		return n
+	}
+	return 1
+}
+
+// Writable 是否可写
+func (p Point) Writable() bool {
+	return p.RW == "RW" || p.RW == "W"
+}
+
+func (p Point) scale() float64 {
+	if p.Scale == 0 {
+		return 1
+	}
+	return p.Scale
+}
+
+func (p Point) mask(w uint16) uint16 {
+	if p.Mask != 0 {
+		return w & p.Mask
+	}
+	return w
+}
+
+// Value 寄存器值换算为工程值，解析失败见 Decode；引用其他测点的表达式须经 Registers.Collect
+func (p Point) Value(words []uint16) (float64, error) {
+	return p.Compute(words, nil)
+}
+
+// Compute 寄存器值换算为工程值，ref 返回被引用测点的工程值（可为 nil）
+func (p Point) Compute(words []uint16, ref func(field string) (float64, error)) (float64, error) {
+	if p.Eval {
+		// 表达式自行处理位运算，不在解析时套用 Mask
+		p.Mask = 0
+	}
+	raw, err := p.Decode(words)
+	if err != nil || !p.Eval {
+		return raw*p.scale() + p.Offset, err
+	}
+	e, err := p.compiled()
+	if err != nil {
+		return 0, err
+	}
+	if ref == nil {
+		ref = func(field string) (float64, error) {
+			return 0, errors.New("reference to " + field + " outside Collect")
+		}
+	}
+	v, err := e.Eval(raw, ref)
+	if err != nil {
+		return 0, err
+	}
+	return finite(v)
+}
+
+// Convert 整数原始值换算为工程值
+func (p Point) Convert(raw int64) float64 {
+	return float64(raw)*p.scale() + p.Offset
+}
+
+// Inverse 工程值反算原始值（写操作使用）
+func (p Point) Inverse(value float64) int64 {
+	return int64(math.Round((value - p.Offset) / p.scale()))
+}
+
+// RawRange 数据类型可表示的原始值范围
+func (p Point) RawRange() (float64, float64) {
+	if p.Bits > 0 {
+		return 0, float64(uint64(1)<<p.Bits - 1)
+	}
+	if p.Mask != 0 && p.Count() == 1 {
+		return 0, float64(p.Mask)
+	}
+	switch p.Type() {
+	case TypeInt16:
+		return math.MinInt16, math.MaxInt16
+	case TypeUint32:
+		return 0, math.MaxUint32
+	case TypeInt32:
+		return math.MinInt32, math.MaxInt32
+	case TypeUint64:
+		return 0, math.MaxUint64
+	case TypeInt64:
+		return math.MinInt64, math.MaxInt64
+	case TypeFloat32:
+		return -math.MaxFloat32, math.MaxFloat32
+	case TypeFloat64:
+		return -math.MaxFloat64, math.MaxFloat64
+	case TypeBCD16:
+		return 0, 9999
+	case TypeBCD32:
+		return 0, 99999999
+	case TypeString:
+		return 0, 0
+	}
+	return 0, math.MaxUint16
+}
+
+// Range 有效范围，未显式配置时按数据类型与换算推导
+func (p Point) Range() (float64, float64) {
+	if p.Min != 0 || p.Max != 0 {
+		return p.Min, p.Max
+	}
+	if p.Eval {
+		return -math.MaxFloat64, math.MaxFloat64
+	}
+	lo, hi := p.RawRange()
+	min, max := lo*p.scale()+p.Offset, hi*p.scale()+p.Offset
+	if min > max {
+		min, max = max, min
+	}
+	// float64 满量程缩放后可能溢出，describe 的 JSON 不接受 Inf
+	return math.Max(min, -math.MaxFloat64), math.Min(max, math.MaxFloat64)
+}
+
+// Format 按小数位格式化工程值
+func (p Point) Format(value float64) string {
+	return strconv.FormatFloat(value, 'f', p.Decimals, 64)
+}
+
+// Output 生成 handle 输出的测点条目，quality 见 Check；状态量另带 state，
+// 取值不在 States 中时 quality 为 out_of_range
+func (p Point) Output(value float64) map[string]interface{} {
+	quality, reason := p.Check(value)
+	out := map[string]interface{}{
+		"field_name": p.Field,
+		"value":      p.Format(value),
+		"rw":         p.RW,
+		"unit":       p.Unit,
+		"label":      p.Label,
+		"quality":    quality,
+		"reason":     reason,
+	}
+	if len(p.States) > 0 {
+		label, ok := p.StateLabel(value)
+		if !ok && quality == QualityGood {
+			out["quality"], out["reason"] = QualityOutOfRange, "undefined state "+p.Format(value)
+		}
+		out["state"] = label
+	}
+	return out
+}
+
+// Find 按字段名查找测点
+func Find(points []Point, field string) (Point, bool) {
+	for _, p := range points {
+		if p.Field == field {
+			return p, true
+		}
+	}
+	return Point{}, false
+}
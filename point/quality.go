@@ -0,0 +1,84 @@
+package point
+
+import (
+	"strconv"
+
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+)
+
+// 测点数据质量
+const (
+	QualityGood       = "good"         // 读取成功且在有效范围内
+	QualityCommFail   = "comm_fail"    // 超时、响应格式错误或未读取
+	QualityCRCError   = "crc_error"    // 响应 CRC 校验失败
+	QualityException  = "exception"    // 从站返回异常响应
+	QualityOutOfRange = "out_of_range" // 读取成功但超出配置的 min/max
+	QualityNoData     = "no_data"      // 计算测点暂无数据（尚未发生或缺少配置），不是读取失败
+)
+
+// Classify 读取错误对应的数据质量
+func Classify(err error) string {
+	if err == nil {
+		return QualityGood
+	}
+	if err == modbus.ErrCRC {
+		return QualityCRCError
+	}
+	if _, ok := modbus.AsException(err); ok {
+		return QualityException
+	}
+	return QualityCommFail
+}
+
+// Check 检查工程值是否在点表显式配置的 min/max 内
+func (p Point) Check(value float64) (quality, reason string) {
+	if p.Min == 0 && p.Max == 0 {
+		return QualityGood, ""
+	}
+	if value < p.Min || value > p.Max {
+		return QualityOutOfRange, "value " + p.Format(value) + " outside [" +
+			strconv.FormatFloat(p.Min, 'f', -1, 64) + ", " + strconv.FormatFloat(p.Max, 'f', -1, 64) + "]"
+	}
+	return QualityGood, ""
+}
+
+// Invalid 生成读取失败测点的输出条目，value（状态量另有 state）为空
+func (p Point) Invalid(quality, reason string) map[string]interface{} {
+	out := map[string]interface{}{
+		"field_name": p.Field,
+		"value":      "",
+		"rw":         p.RW,
+		"unit":       p.Unit,
+		"label":      p.Label,
+		"quality":    quality,
+		"reason":     reason,
+	}
+	if len(p.States) > 0 {
+		out["state"] = ""
+	}
+	return out
+}
+
+// Result 生成 handle 的标准输出：全部测点读取成功时 success=true（out_of_range
+// 与 no_data 不算失败），否则 success=false，error 给出失败测点数（测点仍全部输出）；
+// errors 列出失败的读请求段
+func Result(points []map[string]interface{}, errs []BlockError) map[string]interface{} {
+	failed := 0
+	for _, pt := range points {
+		if q, _ := pt["quality"].(string); q != "" && q != QualityGood && q != QualityOutOfRange && q != QualityNoData {
+			failed++
+		}
+	}
+	if errs == nil {
+		errs = []BlockError{}
+	}
+	out := map[string]interface{}{
+		"success": failed == 0,
+		"points":  points,
+		"errors":  errs,
+	}
+	if failed > 0 {
+		out["error"] = strconv.Itoa(failed) + "/" + strconv.Itoa(len(points)) + " points failed"
+	}
+	return out
+}
@@ -0,0 +1,28 @@
+package point
+
+import "github.com/gonglijing/xunjiFsu/drvs/modbus"
+
+// ReadBlocks 依次读取各分组，每组只请求一次；失败的分组记入 Fail，
+// 其余分组照常读取
+func ReadBlocks(client *modbus.Client, devAddr byte, blocks []modbus.Block) *Registers {
+	regs := &Registers{}
+	for _, blk := range blocks {
+		values, err := client.ReadRegisters(devAddr, blk.FuncCode, blk.Start, blk.Count)
+		if err != nil {
+			if client.Logf != nil {
+				client.Logf("read fc=%d start=%d count=%d err=%v", blk.FuncCode, blk.Start, blk.Count, err)
+			}
+			regs.Fail(blk.FuncCode, blk.Start, blk.Count, 1, err)
+			continue
+		}
+		regs.Put(blk.FuncCode, blk.Start, values)
+	}
+	return regs
+}
+
+// ReadPoints 读取各分组并按点表输出全部测点（见 Collect）与失败的读请求段，
+// 供 Result 生成 handle 输出
+func ReadPoints(client *modbus.Client, devAddr byte, blocks []modbus.Block, points []Point) ([]map[string]interface{}, []BlockError) {
+	regs := ReadBlocks(client, devAddr, blocks)
+	return regs.Collect(points), regs.Errors()
+}
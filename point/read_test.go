@@ -0,0 +1,36 @@
+package point
+
+import (
+	"testing"
+
+	"github.com/gonglijing/xunjiFsu/drvs/internal/modbustest"
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+)
+
+func TestReadPoints(t *testing.T) {
+	slave := modbustest.NewSlave()
+	slave.Holding[0], slave.Holding[1] = 231, 500
+	points := []Point{
+		{Field: "T", FuncCode: 3, Address: 0, Scale: 0.1, Decimals: 1},
+		{Field: "H", FuncCode: 3, Address: 1},
+		{Field: "X", FuncCode: 3, Address: 10},
+	}
+	blocks := []modbus.Block{{FuncCode: 3, Start: 0, Count: 2}, {FuncCode: 3, Start: 10, Count: 1}}
+
+	values, errs := ReadPoints(modbus.NewRTUClient(slave), 1, blocks, points)
+	if slave.Requests != 2 {
+		t.Errorf("requests = %d, want 2", slave.Requests)
+	}
+	if len(values) != 3 {
+		t.Fatalf("got %d points, want 3", len(values))
+	}
+	if values[0]["value"] != "23.1" || values[0]["quality"] != QualityGood {
+		t.Errorf("T = %v", values[0])
+	}
+	if values[2]["value"] != "" || values[2]["quality"] != QualityException {
+		t.Errorf("X = %v, want exception", values[2])
+	}
+	if len(errs) != 1 || errs[0].Start != 10 || errs[0].Attempts != 1 || errs[0].Exception != modbus.ExceptionIllegalDataAddress {
+		t.Errorf("errors = %+v, want one exception for block 10+1", errs)
+	}
+}
@@ -0,0 +1,176 @@
+package point
+
+import (
+	"errors"
+
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+)
+
+// Registers 已读取的寄存器值与读取失败原因，按 功能码+地址 索引；零值可用
+type Registers struct {
+	values map[uint32]uint16
+	errs   map[uint32]error
+	blocks []BlockError
+}
+
+// BlockError 一个读请求段的失败记录，随 handle 输出供现场排障
+type BlockError struct {
+	FuncCode byte   `json:"func_code"`
+	Start    uint16 `json:"start"`
+	Count    uint16 `json:"count"`
+	Kind     string `json:"kind"` // comm_fail | crc_error | exception，同测点 quality
+	Error    string `json:"error"`
+	// Exception 异常码（kind=exception 时）
+	Exception int `json:"exception_code,omitempty"`
+	// Attempts 该段实际发出的请求次数，0 表示本次未请求（如已知不可读而跳过）
+	Attempts int `json:"attempts"`
+}
+
+func regKey(funcCode byte, addr uint16) uint32 {
+	return uint32(funcCode)<<16 | uint32(addr)
+}
+
+// Put 保存一段从 start 开始的寄存器值
+func (r *Registers) Put(funcCode byte, start uint16, values []uint16) {
+	if r.values == nil {
+		r.values = make(map[uint32]uint16)
+	}
+	for i, v := range values {
+		r.values[regKey(funcCode, start+uint16(i))] = v
+	}
+}
+
+// Fail 记录一段寄存器读取失败：段内各寄存器的原因与一条 BlockError，
+// 已读到的寄存器不受影响
+func (r *Registers) Fail(funcCode byte, start uint16, count uint16, attempts int, err error) {
+	if r.errs == nil {
+		r.errs = make(map[uint32]error)
+	}
+	for i := uint16(0); i < count; i++ {
+		r.errs[regKey(funcCode, start+i)] = err
+	}
+	be := BlockError{
+		FuncCode: funcCode,
+		Start:    start,
+		Count:    count,
+		Kind:     Classify(err),
+		Error:    err.Error(),
+		Attempts: attempts,
+	}
+	if ex, ok := modbus.AsException(err); ok {
+		be.Exception = int(ex.Code)
+	}
+	r.blocks = append(r.blocks, be)
+}
+
+// Errors 按记录顺序返回失败的读请求段
+func (r *Registers) Errors() []BlockError {
+	return r.blocks
+}
+
+// Words 取出测点占用的全部寄存器；任一缺失时返回其失败原因
+func (r *Registers) Words(p Point) ([]uint16, error) {
+	n := p.Count()
+	words := make([]uint16, n)
+	for i := uint16(0); i < n; i++ {
+		key := regKey(p.FuncCode, p.Address+i)
+		v, ok := r.values[key]
+		if !ok {
+			if err := r.errs[key]; err != nil {
+				return nil, err
+			}
+			return nil, errNotRead
+		}
+		words[i] = v
+	}
+	return words, nil
+}
+
+// errNotRead 测点不在任何读取分组中，或所在分组未读取
+var errNotRead = errors.New("register not read")
+
+// Collect 按点表顺序输出全部测点，读取失败的测点带 quality/reason 且 value 为空；
+// 寄存器内容无法按数据类型解析（非法 BCD、NaN）或表达式结果非有限数时 quality 为
+// out_of_range；引用其他测点的表达式在被引用测点失败时沿用其 quality
+func (r *Registers) Collect(points []Point) []map[string]interface{} {
+	c := collector{regs: r, points: points, index: map[string]int{}, results: make([]collected, len(points))}
+	for i, p := range points {
+		c.index[p.Field] = i
+	}
+	out := make([]map[string]interface{}, 0, len(points))
+	for i, p := range points {
+		if p.IsText() {
+			words, err := r.Words(p)
+			if err != nil {
+				out = append(out, p.Invalid(Classify(err), err.Error()))
+			} else {
+				out = append(out, p.OutputText(p.Text(words)))
+			}
+			continue
+		}
+		res := c.value(i)
+		if res.quality != "" {
+			out = append(out, p.Invalid(res.quality, res.reason))
+			continue
+		}
+		out = append(out, p.Output(res.value))
+	}
+	return out
+}
+
+// collected 单个测点的换算结果，quality 非空表示失败
+type collected struct {
+	done    bool
+	busy    bool
+	value   float64
+	quality string
+	reason  string
+}
+
+// collector 按需换算测点，表达式引用的测点先于引用方换算，每个测点只换算一次
+type collector struct {
+	regs    *Registers
+	points  []Point
+	index   map[string]int
+	results []collected
+}
+
+func (c *collector) value(i int) collected {
+	res := &c.results[i]
+	if res.done {
+		return *res
+	}
+	p := c.points[i]
+	if res.busy {
+		return collected{quality: QualityOutOfRange, reason: "circular reference to " + p.Field}
+	}
+	res.busy = true
+	words, err := c.regs.Words(p)
+	if err != nil {
+		res.quality, res.reason = Classify(err), err.Error()
+	} else {
+		var refFailed *collected
+		v, err := p.Compute(words, func(field string) (float64, error) {
+			j, ok := c.index[field]
+			if !ok || c.points[j].IsText() {
+				return 0, errors.New("unknown point " + field)
+			}
+			ref := c.value(j)
+			if ref.quality != "" {
+				refFailed = &ref
+				return 0, errors.New(field + ": " + ref.reason)
+			}
+			return ref.value, nil
+		})
+		switch {
+		case refFailed != nil:
+			res.quality, res.reason = refFailed.quality, err.Error()
+		case err != nil:
+			res.quality, res.reason = QualityOutOfRange, err.Error()
+		default:
+			res.value = v
+		}
+	}
+	res.busy, res.done = false, true
+	return *res
+}
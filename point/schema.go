@@ -0,0 +1,91 @@
+package point
+
+// ConfigKey 驱动接受的 config 键
+type ConfigKey struct {
+	Key     string `json:"key"`
+	Type    string `json:"type"` // "int" | "float" | "string" | "bool" | "json"
+	Default string `json:"default,omitempty"`
+	Desc    string `json:"desc"`
+}
+
+// CommonConfig 所有驱动共用的 config 键
+var CommonConfig = []ConfigKey{
+	{Key: "device_address", Type: "int", Default: "1", Desc: "Modbus 从站地址"},
+	{Key: "func_name", Type: "string", Default: "read", Desc: "操作类型 read | write"},
+	{Key: "field_name", Type: "string", Desc: "写操作字段名"},
+	{Key: "value", Type: "string", Desc: "写操作的值（工程值）"},
+	{Key: "debug", Type: "bool", Default: "false", Desc: "输出收发调试日志"},
+}
+
+// Schema describe 导出的点表描述
+type Schema struct {
+	Version  string        `json:"version"`
+	Protocol string        `json:"protocol"` // "modbus-rtu" | "modbus-tcp"
+	Points   []PointSchema `json:"points"`
+	Config   []ConfigKey   `json:"config"`
+}
+
+// PointSchema 单个测点的描述
+type PointSchema struct {
+	Field    string  `json:"field_name"`
+	Label    string  `json:"label"`
+	Unit     string  `json:"unit"`
+	RW       string  `json:"rw"`
+	FuncCode int     `json:"func_code"`
+	Address  int     `json:"address"`
+	Length   int     `json:"length"`
+	DataType string  `json:"data_type"`
+	Order    string  `json:"byte_order,omitempty"`
+	Mask     int     `json:"mask,omitempty"`
+	Bit      int     `json:"bit,omitempty"`
+	Bits     int     `json:"bits,omitempty"`
+	Scale    float64 `json:"scale"`
+	Offset   float64 `json:"offset"`
+	Expr     string  `json:"expression,omitempty"`
+	Eval     bool    `json:"eval,omitempty"` // true 时按 expression 求值，scale/offset 不生效
+	Decimals int     `json:"decimals"`
+	States   []State `json:"states,omitempty"` // 状态量的全部取值
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+}
+
+// Describe 生成点表描述，extra 为驱动特有的 config 键
+func Describe(version, protocol string, points []Point, extra ...ConfigKey) Schema {
+	s := Schema{
+		Version:  version,
+		Protocol: protocol,
+		Points:   make([]PointSchema, 0, len(points)),
+		Config:   append(append([]ConfigKey{}, CommonConfig...), extra...),
+	}
+	for _, p := range points {
+		s.Points = append(s.Points, p.Schema())
+	}
+	return s
+}
+
+// Schema 单个测点的描述
+func (p Point) Schema() PointSchema {
+	min, max := p.Range()
+	return PointSchema{
+		Field:    p.Field,
+		Label:    p.Label,
+		Unit:     p.Unit,
+		RW:       p.RW,
+		FuncCode: int(p.FuncCode),
+		Address:  int(p.Address),
+		Length:   int(p.Count()),
+		DataType: p.Type(),
+		Order:    p.Order,
+		Mask:     int(p.Mask),
+		Bit:      int(p.Bit),
+		Bits:     int(p.Bits),
+		Scale:    p.scale(),
+		Offset:   p.Offset,
+		Expr:     p.Expr,
+		Eval:     p.Eval,
+		Decimals: p.Decimals,
+		States:   p.States,
+		Min:      min,
+		Max:      max,
+	}
+}
@@ -0,0 +1,196 @@
+package point
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// tableEntry 运行时点表条目，字段与 PointSchema 的 JSON 名一致
+type tableEntry struct {
+	Field    string   `json:"field_name"`
+	Label    string   `json:"label"`
+	Unit     string   `json:"unit"`
+	RW       string   `json:"rw"`
+	FuncCode int      `json:"func_code"`
+	Address  int      `json:"address"`
+	Length   int      `json:"length"`
+	DataType string   `json:"data_type"`
+	Order    string   `json:"byte_order"`
+	Mask     int      `json:"mask"`
+	Bit      int      `json:"bit"`
+	Bits     int      `json:"bits"`
+	Scale    *float64 `json:"scale"`
+	Offset   float64  `json:"offset"`
+	Expr     string   `json:"expression"`
+	Eval     bool     `json:"eval"`
+	Decimals int      `json:"decimals"`
+	// States 状态集名称、"0=分闸;1=合闸" 或 describe 输出的 [{"value":0,"label":"分闸"}]
+	States json.RawMessage `json:"states"`
+	Min    *float64        `json:"min"`
+	Max    *float64        `json:"max"`
+}
+
+// ParseTable 解析 JSON 数组形式的点表（通过 config 下发）。
+//
+// 条目字段同 describe 输出的 points：func_code 缺省 3；byte_order 缺省 ABCD；
+// string 类型须给出 length；bit/bits 从整数值中取位字段（如 bit=15,bits=1
+// 取开关状态位）；mask 只用于 16 位类型；未给出 scale 时由 expression（如
+// v/10-40、bitand(v,32768)）推导 Scale/Offset/Mask，不能化简为线性换算的
+// 表达式、与其他运算组合或用于多寄存器类型的 bitand（或 eval=true）在采集时
+// 按 Expr 求值，见 CompileExpr。
+func ParseTable(s string) ([]Point, error) {
+	var entries []tableEntry
+	if err := json.Unmarshal([]byte(s), &entries); err != nil {
+		return nil, errors.New("points: " + err.Error())
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("points: empty table")
+	}
+
+	points := make([]Point, 0, len(entries))
+	seen := map[string]bool{}
+	for i, e := range entries {
+		p, err := e.point()
+		if err != nil {
+			name := e.Field
+			if name == "" {
+				name = "#" + strconv.Itoa(i+1)
+			}
+			return nil, errors.New("points[" + name + "]: " + err.Error())
+		}
+		if seen[p.Field] {
+			return nil, errors.New("points[" + p.Field + "]: duplicate field_name")
+		}
+		seen[p.Field] = true
+		points = append(points, p)
+	}
+	if err := CheckRefs(points); err != nil {
+		return nil, errors.New("points: " + err.Error())
+	}
+	return points, nil
+}
+
+func (e tableEntry) point() (Point, error) {
+	var err error
+	p := Point{
+		Field:    e.Field,
+		Label:    e.Label,
+		Unit:     e.Unit,
+		RW:       e.RW,
+		DataType: strings.ToLower(e.DataType),
+		Order:    strings.ToUpper(e.Order),
+		Offset:   e.Offset,
+		Expr:     e.Expr,
+		Decimals: e.Decimals,
+	}
+	if p.Field == "" {
+		return p, errors.New("missing field_name")
+	}
+	if p.Label == "" {
+		p.Label = p.Field
+	}
+	if p.RW == "" {
+		p.RW = "R"
+	}
+
+	switch e.FuncCode {
+	case 0, 3:
+		p.FuncCode = 3
+	case 4:
+		p.FuncCode = 4
+	default:
+		return p, errors.New("func_code must be 3 or 4")
+	}
+	if !ValidType(p.DataType) {
+		return p, errors.New("unsupported data_type " + strconv.Quote(e.DataType))
+	}
+	if !ValidOrder(p.Order) {
+		return p, errors.New("byte_order must be ABCD, CDAB, BADC or DCBA")
+	}
+	if e.Address < 0 || e.Address > 0xFFFF {
+		return p, errors.New("address out of range")
+	}
+	p.Address = uint16(e.Address)
+	if e.Length < 0 || e.Length > 125 {
+		return p, errors.New("length out of range")
+	}
+	p.Length = uint16(e.Length)
+	if p.IsText() && p.Length == 0 {
+		return p, errors.New("string needs length")
+	}
+	if n, ok := typeWords[p.Type()]; ok && p.Length != 0 && p.Length != n {
+		return p, errors.New(p.Type() + " needs length " + strconv.Itoa(int(n)))
+	}
+	if int(p.Address)+int(p.Count()) > 0x10000 {
+		return p, errors.New("address + length exceeds 65535")
+	}
+	if e.Mask < 0 || e.Mask > 0xFFFF {
+		return p, errors.New("mask out of range")
+	}
+	if e.Mask != 0 && p.Count() > 1 {
+		return p, errors.New("mask applies to 16-bit types only")
+	}
+	p.Mask = uint16(e.Mask)
+	if e.Bit < 0 || e.Bit > 63 || e.Bits < 0 || e.Bits > 64 {
+		return p, errors.New("bit/bits out of range")
+	}
+	p.Bit, p.Bits = uint8(e.Bit), uint8(e.Bits)
+
+	switch {
+	case e.Eval:
+		if _, err := CompileExpr(e.Expr); err != nil {
+			return p, err
+		}
+		p.Eval = true
+	case e.Scale != nil:
+		p.Scale = *e.Scale
+	case e.Expr != "":
+		scale, offset, mask, err := ParseLinear(e.Expr)
+		if err != nil {
+			if _, cerr := CompileExpr(e.Expr); cerr != nil {
+				return p, cerr
+			}
+			p.Eval = true
+			break
+		}
+		switch {
+		case mask == 0:
+			p.Scale, p.Offset = scale, offset
+		case p.Count() == 1:
+			p.Mask = mask
+		default:
+			// Mask 只作用于单个寄存器，多寄存器类型按表达式求值
+			p.Eval = true
+		}
+	}
+	if err := p.CheckBits(); err != nil {
+		return p, err
+	}
+	if p.States, err = e.states(); err != nil {
+		return p, err
+	}
+	if e.Min != nil {
+		p.Min = *e.Min
+	}
+	if e.Max != nil {
+		p.Max = *e.Max
+	}
+	return p, nil
+}
+
+func (e tableEntry) states() ([]State, error) {
+	if len(e.States) == 0 || string(e.States) == "null" {
+		return nil, nil
+	}
+	var text string
+	if json.Unmarshal(e.States, &text) == nil {
+		return ParseStates(text)
+	}
+	var states []State
+	if err := json.Unmarshal(e.States, &states); err != nil {
+		return nil, errors.New("states: want a name, \"value=label;…\" or [{value,label}]")
+	}
+	return states, checkStates(states)
+}
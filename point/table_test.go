@@ -0,0 +1,41 @@
+package point
+
+import "testing"
+
+func parseOne(t *testing.T, entry string) Point {
+	t.Helper()
+	points, err := ParseTable("[" + entry + "]")
+	if err != nil {
+		t.Fatalf("ParseTable(%s): %v", entry, err)
+	}
+	return points[0]
+}
+
+func TestParseTableBitand(t *testing.T) {
+	tests := []struct {
+		entry string
+		words []uint16
+		want  float64
+	}{
+		// 整个表达式为 bitand 的 16 位测点走 Mask
+		{`{"field_name": "A", "address": 0, "expression": "bitand(v,32768)"}`, []uint16{0x8001}, 32768},
+		// 组合表达式按表达式求值：v=3 时 3+1
+		{`{"field_name": "B", "address": 0, "expression": "v+bitand(v,1)"}`, []uint16{3}, 4},
+		// 多寄存器类型不套用 16 位 Mask：0x0001_0203 & 255
+		{`{"field_name": "C", "address": 0, "data_type": "uint32", "expression": "bitand(v,255)"}`, []uint16{0x0001, 0x0203}, 3},
+	}
+	for _, tt := range tests {
+		p := parseOne(t, tt.entry)
+		got, err := p.Value(tt.words)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: Value(%#x) = %v, %v; want %v (mask %#x, eval %v)", p.Field, tt.words, got, err, tt.want, p.Mask, p.Eval)
+		}
+	}
+}
+
+func TestParseTableRejectsWideMask(t *testing.T) {
+	_, err := ParseTable(`[{"field_name": "C", "address": 0, "data_type": "uint32", "mask": 255}]`)
+	if err == nil || err.Error() != "points[C]: mask applies to 16-bit types only" {
+		t.Errorf("err = %v", err)
+	}
+}
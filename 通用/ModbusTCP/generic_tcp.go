@@ -0,0 +1,75 @@
+// =============================================================================
+// 通用 Modbus TCP 驱动（点表由 config 下发）
+// =============================================================================
+//
+// 不内置点表，网关在 config.points 中下发 JSON 数组，字段同 describe 输出:
+//
+//	{"config": {"device_address": "1", "max_registers": "60", "points": [
+//	  {"field_name": "TEM", "label": "温度", "unit": "℃", "func_code": 4,
+//	   "address": 48, "length": 1, "data_type": "int16", "expression": "v/10", "decimals": 1}
+//	]}}
+//
+// points 也可以是 JSON 字符串。驱动按功能码与地址合并读请求（单次不超过
+// max_registers 个寄存器，间隔不超过 max_gap 的测点一并读取），再按点表
+// 换算工程值。读取与换算在共用包 generic 中，本文件只声明宿主收发函数。
+//
+// Host 提供: tcp_transceive
+//
+// =============================================================================
+package main
+
+import (
+	"github.com/gonglijing/xunjiFsu/drvs/driver"
+	"github.com/gonglijing/xunjiFsu/drvs/generic"
+)
+
+// =============================================================================
+// 【固定不变】Host 函数声明
+// =============================================================================
+//
+//go:wasmimport extism:host/user tcp_transceive
+func tcp_transceive(wPtr uint64, wSize uint64, rPtr uint64, rCap uint64, timeoutMs uint64) uint64
+
+// =============================================================================
+// 【用户修改】驱动版本
+// =============================================================================
+const DriverVersion = "1.0.0"
+
+// =============================================================================
+// 【固定不变】驱动入口
+// =============================================================================
+//
+//go:wasmexport handle
+func handle() int32 {
+	defer driver.Recover()
+
+	cfg := driver.GetConfig()
+	client := driver.NewTCPClient(tcp_transceive, cfg.Debug)
+	driver.OutputJSON(generic.Handle(client, cfg))
+	return 0
+}
+
+// =============================================================================
+// 【固定不变】描述点表
+// =============================================================================
+//
+//go:wasmexport describe
+func describe() int32 {
+	driver.OutputJSON(map[string]interface{}{
+		"success": true,
+		"data":    generic.Describe(DriverVersion, "modbus-tcp", driver.GetConfig()),
+	})
+	return 0
+}
+
+// =============================================================================
+// 【固定不变】驱动版本
+// =============================================================================
+//
+//go:wasmexport version
+func version() int32 {
+	driver.OutputVersion(DriverVersion)
+	return 0
+}
+
+func main() {}
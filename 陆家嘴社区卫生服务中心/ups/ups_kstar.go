@@ -16,6 +16,10 @@
 //   - 输入频率(IH): FC=03, 地址=109, 长度=1, 缩放=0.1
 //   - 电池容量(qos): FC=03, 地址=100, 长度=1, 缩放=0.1
 //   - 电池剩余时间(ltime): FC=03, 地址=101, 长度=1, 缩放=1
+//   - 供电模式、告警位与电池电压、输出电流、有功功率: 由 config.points 给出
+//     （科士达各机型地址不同，不设默认值），告警位汇总为 alarms，见 statusPoints
+//   - func_name=battery_test|cancel_test|mute|shutdown_with_delay 按 config.control
+//     下发控制命令（FC05/FC06/FC16），须带 confirm；自检结束后读回结果，见 checkTest
 //
 // Host 提供: tcp_transceive
 //
@@ -23,12 +27,15 @@
 package main
 
 import (
-	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"strconv"
 	"strings"
+	"time"
 
-	pdk "github.com/extism/go-pdk"
+	"github.com/gonglijing/xunjiFsu/drvs/driver"
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+	"github.com/gonglijing/xunjiFsu/drvs/point"
 )
 
 // =============================================================================
@@ -39,42 +46,49 @@ import (
 func tcp_transceive(wPtr uint64, wSize uint64, rPtr uint64, rCap uint64, timeoutMs uint64) uint64
 
 // =============================================================================
-// 【固定不变】配置结构（网关传入）
+// 【用户修改】驱动版本
 // =============================================================================
-type DriverConfig struct {
-	DeviceAddress int    `json:"device_address"` // Modbus 从站地址
-	FuncName      string `json:"func_name"`      // "read" | "write"
-	FieldName     string `json:"field_name"`     // 可写字段名
-	Value         string `json:"value"`          // 写操作的值
-}
+const DriverVersion = "1.0.0"
 
 // =============================================================================
-// 【用户修改】驱动版本
+// 【用户修改】点表配置
 // =============================================================================
-const DriverVersion = "1.0.0"
+// 【自动生成】pointgen
+// 由 points.xlsx 生成，请勿手工修改；修改点表后执行 make points
+var pointConfig = []point.Point{
+	{Field: "OUR", FuncCode: modbus.FuncReadHolding, Address: 120, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "R", Unit: "V", Label: "R相输出电压"},
+	{Field: "OUS", FuncCode: modbus.FuncReadHolding, Address: 121, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "R", Unit: "V", Label: "S相输出电压"},
+	{Field: "OUT", FuncCode: modbus.FuncReadHolding, Address: 122, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "R", Unit: "V", Label: "T相输出电压"},
+	{Field: "OH", FuncCode: modbus.FuncReadHolding, Address: 119, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "R", Unit: "Hz", Label: "输出频率"},
+	{Field: "loadR", FuncCode: modbus.FuncReadHolding, Address: 123, Length: 1, DataType: point.TypeInt16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "%", Label: "R相负载率"},
+	{Field: "loadS", FuncCode: modbus.FuncReadHolding, Address: 124, Length: 1, DataType: point.TypeInt16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "%", Label: "S相负载率"},
+	{Field: "loadT", FuncCode: modbus.FuncReadHolding, Address: 125, Length: 1, DataType: point.TypeInt16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "%", Label: "T相负载率"},
+	{Field: "IUR", FuncCode: modbus.FuncReadHolding, Address: 110, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "R", Unit: "V", Label: "R相输入电压"},
+	{Field: "IUS", FuncCode: modbus.FuncReadHolding, Address: 111, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "R", Unit: "V", Label: "S相输入电压"},
+	{Field: "IOT", FuncCode: modbus.FuncReadHolding, Address: 112, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "R", Unit: "V", Label: "T相输入电压"},
+	{Field: "IH", FuncCode: modbus.FuncReadHolding, Address: 109, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "R", Unit: "Hz", Label: "输入频率"},
+	{Field: "qos", FuncCode: modbus.FuncReadHolding, Address: 100, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "R", Unit: "%", Label: "电池容量"},
+	{Field: "ltime", FuncCode: modbus.FuncReadHolding, Address: 101, Length: 1, DataType: point.TypeInt16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "min", Label: "电池剩余时间"},
+}
+
+// 读取分组
+var readBlocks = []modbus.Block{
+	{FuncCode: modbus.FuncReadHolding, Start: 100, Count: 2},
+	{FuncCode: modbus.FuncReadHolding, Start: 109, Count: 4},
+	{FuncCode: modbus.FuncReadHolding, Start: 119, Count: 7},
+}
+
+// 【自动生成】结束
 
 // =============================================================================
-// 【用户修改】点表定义
+// 【用户修改】配置项
 // =============================================================================
-const (
-	REG_BATTERY_CAPACITY    = 100 // 电池容量 qos
-	REG_BATTERY_REMAIN_TIME = 101 // 电池剩余时间 ltime
-
-	REG_INPUT_FREQUENCY = 109 // 输入频率 IH
-	REG_INPUT_VOLTAGE_R = 110 // R相输入电压 IUR
-	REG_INPUT_VOLTAGE_S = 111 // S相输入电压 IUS
-	REG_INPUT_VOLTAGE_T = 112 // T相输入电压 IOT
-
-	REG_OUTPUT_FREQUENCY = 119 // 输出频率 OH
-	REG_OUTPUT_VOLTAGE_R = 120 // R相输出电压 OUR
-	REG_OUTPUT_VOLTAGE_S = 121 // S相输出电压 OUS
-	REG_OUTPUT_VOLTAGE_T = 122 // T相输出电压 OUT
-	REG_LOAD_PERCENT_R   = 123 // R相负载率 loadR
-	REG_LOAD_PERCENT_S   = 124 // S相负载率 loadS
-	REG_LOAD_PERCENT_T   = 125 // T相负载率 loadT
-
-	FUNC_CODE_READ = 0x03 // 读保持寄存器
-)
+var configKeys = []point.ConfigKey{
+	{Key: "points", Type: "json", Desc: "状态/告警位与补充模拟量点表 JSON 数组，字段同 describe 的 points，见 README"},
+	{Key: "control", Type: "json", Desc: "控制命令映射 {\"battery_test\":{\"func_code\":6,\"address\":200,\"value\":1},…}，见 README"},
+	{Key: "confirm", Type: "string", Desc: "控制命令确认口令：命令名，shutdown_with_delay 为 shutdown_with_delay:<value>"},
+	{Key: "test_wait", Type: "int", Default: "60", Desc: "电池自检下发后至少等待多少秒再读回结果"},
+}
 
 // =============================================================================
 // 【固定不变】驱动入口
@@ -82,31 +96,48 @@ const (
 //
 //go:wasmexport handle
 func handle() int32 {
-	defer func() {
-		if r := recover(); r != nil {
-			outputJSON(map[string]interface{}{"success": false, "error": "panic"})
-		}
-	}()
+	defer driver.Recover()
 
-	cfg := getConfig()
-	points := readAllUPS(cfg.DeviceAddress)
+	cfg := driver.GetConfig()
+	client := driver.NewTCPClient(tcp_transceive, cfg.Debug)
+	if name := strings.ToLower(cfg.FuncName); name != "read" {
+		driver.OutputJSON(runCommand(client, cfg, name, time.Now().Unix()))
+		return 0
+	}
 
-	outputJSON(map[string]interface{}{
-		"success": true,
-		"points":  points,
-	})
+	extra, extraBlocks, err := statusPoints(cfg)
+	if err != nil {
+		driver.OutputJSON(map[string]interface{}{"success": false, "error": "config." + err.Error()})
+		return 0
+	}
+	devAddr := byte(cfg.DeviceAddress)
+	points, errs := point.ReadPoints(client, devAddr, readBlocks, pointConfig)
+	status, statusErrs := point.ReadPoints(client, devAddr, extraBlocks, extra)
+	points, errs = append(points, status...), append(errs, statusErrs...)
+
+	result := point.Result(points, errs)
+	result["alarms"] = activeAlarms(status)
+	test, events := checkTest(client, cfg, time.Now().Unix())
+	result["battery_test"] = test
+	result["events"] = events
+	driver.OutputJSON(result)
 	return 0
 }
 
 // =============================================================================
-// 【固定不变】描述可写字段
+// 【固定不变】描述点表
 // =============================================================================
 //
 //go:wasmexport describe
 func describe() int32 {
-	outputJSON(map[string]interface{}{
+	// config.points 有误时只描述内置点表
+	table := pointConfig
+	if extra, _, err := statusPoints(driver.GetConfig()); err == nil {
+		table = append(append([]point.Point{}, pointConfig...), extra...)
+	}
+	driver.OutputJSON(map[string]interface{}{
 		"success": true,
-		"data":    map[string]string{},
+		"data":    point.Describe(DriverVersion, "modbus-tcp", table, configKeys...),
 	})
 	return 0
 }
@@ -117,198 +148,284 @@ func describe() int32 {
 //
 //go:wasmexport version
 func version() int32 {
-	outputJSON(map[string]interface{}{
-		"success": true,
-		"data": map[string]string{
-			"version": DriverVersion,
-		},
-	})
+	driver.OutputVersion(DriverVersion)
 	return 0
 }
 
 // =============================================================================
-// 【用户修改】读取所有测点
+// 【用户修改】状态与告警测点
 // =============================================================================
-func readAllUPS(devAddr int) []map[string]interface{} {
-	points := make([]map[string]interface{}, 0)
+//
+// 供电模式（市电/电池/旁路）、告警位（市电异常、电池低压、过载、逆变器故障等）
+// 以及电池电压、输出电流、有功功率的寄存器地址、位定义与缩放随科士达机型和
+// 协议版本不同，仓库中没有可核对的点表，驱动不设默认值，由 config.points
+// 按 UPS 随机协议文档给出（格式同通用驱动的点表，见 point.ParseTable）：
+//
+//	[{"field_name": "MAINS_FAIL", "label": "市电异常", "address": 130, "bit": 0, "bits": 1, "states": "alarm"},
+//	 {"field_name": "POWER_MODE", "label": "供电模式", "address": 131, "states": "0=市电;1=电池;2=旁路"},
+//	 {"field_name": "BATT_U", "label": "电池电压", "address": 102, "expression": "v/10", "decimals": 1, "unit": "V"}]
+//
+// 告警位用 states=alarm（0 正常 / 1 告警）；本次读到处于“告警”的测点输出在
+// alarms 中，便于网关直接据此告警。
 
-	if values := readMultipleRegs(byte(devAddr), REG_OUTPUT_FREQUENCY, 7); values != nil {
-		points = append(points, makePoint("OUR", int(values[1]), 0.1, 1, "R", "V", "R相输出电压"))
-		points = append(points, makePoint("OUS", int(values[2]), 0.1, 1, "R", "V", "S相输出电压"))
-		points = append(points, makePoint("OUT", int(values[3]), 0.1, 1, "R", "V", "T相输出电压"))
-		points = append(points, makePoint("OH", int(values[0]), 0.1, 1, "R", "Hz", "输出频率"))
-		points = append(points, makePoint("loadR", int(values[4]), 1, 0, "R", "%", "R相负载率"))
-		points = append(points, makePoint("loadS", int(values[5]), 1, 0, "R", "%", "S相负载率"))
-		points = append(points, makePoint("loadT", int(values[6]), 1, 0, "R", "%", "T相负载率"))
-	}
+// 告警位取值为告警时的状态含义
+var alarmLabel = point.StatesAlarm[1].Label
 
-	if values := readMultipleRegs(byte(devAddr), REG_INPUT_FREQUENCY, 4); values != nil {
-		points = append(points, makePoint("IUR", int(values[1]), 0.1, 1, "R", "V", "R相输入电压"))
-		points = append(points, makePoint("IUS", int(values[2]), 0.1, 1, "R", "V", "S相输入电压"))
-		points = append(points, makePoint("IOT", int(values[3]), 0.1, 1, "R", "V", "T相输入电压"))
-		points = append(points, makePoint("IH", int(values[0]), 0.1, 1, "R", "Hz", "输入频率"))
+// statusPoints 解析 config.points 并按功能码分组，未配置时返回空
+func statusPoints(cfg driver.Config) ([]point.Point, []modbus.Block, error) {
+	if strings.TrimSpace(cfg.Raw["points"]) == "" {
+		return nil, nil, nil
 	}
-
-	if values := readMultipleRegs(byte(devAddr), REG_BATTERY_CAPACITY, 2); values != nil {
-		points = append(points, makePoint("qos", int(values[0]), 0.1, 1, "R", "%", "电池容量"))
-		points = append(points, makePoint("ltime", int(values[1]), 1, 0, "R", "min", "电池剩余时间"))
+	extra, err := point.ParseTable(cfg.Raw["points"])
+	if err != nil {
+		return nil, nil, err
 	}
-
-	return points
+	for _, p := range extra {
+		if _, ok := point.Find(pointConfig, p.Field); ok {
+			return nil, nil, errors.New("points[" + p.Field + "]: duplicates a built-in point")
+		}
+	}
+	blocks, err := point.Plan(extra, modbus.MaxReadCount, 0)
+	if err != nil {
+		return nil, nil, errors.New("points: " + err.Error())
+	}
+	return extra, blocks, nil
 }
 
-func makePoint(field string, rawVal int, scale float64, decimals int, rw, unit, label string) map[string]interface{} {
-	realVal := float64(rawVal) * scale
-	return map[string]interface{}{
-		"field_name": field,
-		"value":      formatFloat(realVal, decimals),
-		"rw":         rw,
-		"unit":       unit,
-		"label":      label,
+// activeAlarms 本次读到处于告警状态的测点
+func activeAlarms(points []map[string]interface{}) []map[string]interface{} {
+	alarms := []map[string]interface{}{}
+	for _, pt := range points {
+		if pt["state"] == alarmLabel && pt["quality"] == point.QualityGood {
+			alarms = append(alarms, map[string]interface{}{"field_name": pt["field_name"], "label": pt["label"]})
+		}
 	}
+	return alarms
 }
 
 // =============================================================================
-// 【固定不变】Modbus TCP 通信函数
+// 【用户修改】控制命令
 // =============================================================================
+//
+// 科士达各机型的控制寄存器不同，驱动不设默认值，由 config.control 给出：
+//
+//	{"battery_test":        {"func_code": 6, "address": 200, "value": 1},
+//	 "cancel_test":         {"func_code": 6, "address": 200, "value": 0},
+//	 "mute":                {"func_code": 5, "address": 10},
+//	 "shutdown_with_delay": {"func_code": 16, "address": 201},
+//	 "test_status":         {"address": 130, "bit": 5},
+//	 "test_result":         {"address": 140, "states": "0=未测试;1=通过;2=失败"}}
+//
+// func_code 为 5（写线圈，value 非 0 为 ON，缺省 ON）、6 或 16（写单个寄存器），
+// 见 modbus.Command；shutdown_with_delay 写入 config.value（按 UPS 协议的单位，
+// 如分钟）。自检、消音等命令寄存器多为自动清零，缺省不读回；能稳定读回的
+// 配置 "verify": true。每条命令须在 config.confirm 中给出口令（命令名，关机为
+// shutdown_with_delay:<value>）。
+//
+// 电池自检下发成功（verify 时读回一致）后把开始时间保存在 Extism var
+// （battery_test@<从站地址>）。之后的轮询在距下发不少于 test_wait 秒、且
+// control.test_status（自检中标志位，若配置）读取成功并为 0 时，按
+// control.test_result 读回结果，输出一条自检记录（events）；读取失败时下次轮询
+// 重试，原因见 battery_test.error，cancel_test 放弃等待。test_status 与
+// test_result 在下发 battery_test 前校验，有误时不下发。
 
-func readMultipleRegs(devAddr byte, startReg uint16, count uint16) []int16 {
-	req := buildReadRequest(devAddr, startReg, count)
-	resp := make([]byte, 64)
+const testVar = "battery_test"
 
-	n := tcpTransceive(req, resp, 1000)
-	if n < 7 {
-		return nil
-	}
+// 缺省自检等待时间（秒）
+const DEFAULT_TEST_WAIT = 60
 
-	values, err := parseReadResponse(resp[:n], devAddr)
-	if err != nil || len(values) < int(count) {
-		return nil
-	}
+// upsCommands 支持的命令
+var upsCommands = map[string]bool{"battery_test": true, "cancel_test": true, "mute": true, "shutdown_with_delay": true}
 
-	result := make([]int16, count)
-	for i := 0; i < int(count); i++ {
-		result[i] = int16(values[i])
-	}
-	return result
+// register control.test_status / control.test_result：自检中标志与自检结果寄存器
+type register struct {
+	FuncCode byte    `json:"func_code"` // 3 | 4，缺省 3
+	Address  *uint16 `json:"address"`
+	Bit      uint8   `json:"bit"`    // test_status：自检中标志位（0 为最低位）
+	States   string  `json:"states"` // test_result：如 "0=通过;1=失败"，见 point.ParseStates
+
+	states []point.State
 }
 
-func tcpTransceive(req []byte, resp []byte, timeoutMs int) int {
-	if len(req) == 0 || len(resp) == 0 {
-		return 0
-	}
+// testState 跨轮询保存的自检状态
+type testState struct {
+	Pending bool        `json:"pending"`         // 已下发、尚未读回结果
+	Start   int64       `json:"start"`           // 下发时间（Unix 秒）
+	Last    *testRecord `json:"last"`            // 上次自检结果
+	Error   string      `json:"error,omitempty"` // 本次未能读回结果的原因，不保存
+}
 
-	reqMem := pdk.AllocateBytes(req)
-	defer reqMem.Free()
-	respMem := pdk.Allocate(len(resp))
-	defer respMem.Free()
+// testRecord 一次自检的结果，也作为 events 输出
+type testRecord struct {
+	Type   string `json:"type"` // "battery_test"
+	Start  int64  `json:"start"`
+	End    int64  `json:"end"` // 读回结果的时间
+	Value  *int   `json:"value"`
+	Result string `json:"result"` // 结果含义，未配置 test_result 时为空
+}
 
-	n := int(tcp_transceive(
-		reqMem.Offset(), uint64(len(req)),
-		respMem.Offset(), uint64(len(resp)),
-		uint64(timeoutMs),
-	))
-	if n <= 0 {
-		return n
+func runCommand(client *modbus.Client, cfg driver.Config, name string, now int64) map[string]interface{} {
+	fail := func(msg string) map[string]interface{} {
+		return map[string]interface{}{"success": false, "error": msg}
+	}
+	if !upsCommands[name] {
+		return fail("unsupported func_name: " + name)
+	}
+	// 命令寄存器多为写后自动清零，缺省不读回
+	cmd, err := modbus.LookupCommand(cfg.Raw["control"], name, false,
+		modbus.FuncWriteCoil, modbus.FuncWriteSingle, modbus.FuncWriteMultiple)
+	if err != nil {
+		return fail(err.Error())
 	}
-	if n > len(resp) {
-		n = len(resp)
+	if cmd.Address == nil {
+		return fail("control." + name + ": missing address")
+	}
+	if name == "battery_test" {
+		var control map[string]json.RawMessage
+		json.Unmarshal([]byte(cfg.Raw["control"]), &control)
+		for _, reg := range []string{"test_status", "test_result"} {
+			if _, err := parseRegister(control, reg); err != nil {
+				return fail(err.Error())
+			}
+		}
 	}
 
-	mem := pdk.NewMemory(respMem.Offset(), uint64(n))
-	mem.Load(resp[:n])
-	return n
-}
+	token, value := name, cmd.WriteValue()
+	if name == "shutdown_with_delay" {
+		n, err := strconv.ParseUint(strings.TrimSpace(cfg.Value), 10, 16)
+		if err != nil {
+			return fail("shutdown_with_delay needs value 0~65535")
+		}
+		value = uint16(n)
+		token = name + ":" + strconv.FormatUint(n, 10)
+	}
+	if err := modbus.Confirm(cfg.Raw["confirm"], token); err != nil {
+		return fail(err.Error())
+	}
 
-func buildReadRequest(addr byte, startReg uint16, count uint16) []byte {
-	mbap := make([]byte, 12)
-	mbap[0] = 0x00
-	mbap[1] = 0x01
-	mbap[2] = 0x00
-	mbap[3] = 0x00
-	mbap[4] = 0x00
-	mbap[5] = 0x06
-	mbap[6] = addr
-
-	mbap[7] = FUNC_CODE_READ
-	mbap[8] = byte(startReg >> 8)
-	mbap[9] = byte(startReg)
-	mbap[10] = byte(count >> 8)
-	mbap[11] = byte(count)
-
-	return mbap
+	res := client.Execute(byte(cfg.DeviceAddress), name, cmd, *cmd.Address, value)
+	if res.Err != nil {
+		return res.Output()
+	}
+
+	// 只有确认执行的自检命令才改变自检状态
+	if name == "battery_test" || name == "cancel_test" {
+		key := driver.StateKey(testVar, cfg.DeviceAddress)
+		var test testState
+		driver.LoadState(key, &test)
+		test.Pending = name == "battery_test"
+		if test.Pending {
+			test.Start = now
+		}
+		driver.SaveState(key, test)
+	}
+	return res.Output()
 }
 
-func parseReadResponse(data []byte, addr byte) ([]uint16, error) {
-	pdu := data[6:]
-	if len(pdu) < 3 {
-		return nil, errf("响应数据不完整")
+// checkTest 自检等待结束后读回结果；返回当前自检状态与本次读回的自检记录
+func checkTest(client *modbus.Client, cfg driver.Config, now int64) (testState, []testRecord) {
+	key := driver.StateKey(testVar, cfg.DeviceAddress)
+	var test testState
+	driver.LoadState(key, &test)
+	events := []testRecord{}
+	if !test.Pending {
+		return test, events
 	}
 
-	if pdu[0] != addr || pdu[1] != FUNC_CODE_READ {
-		return nil, errf("响应地址或功能码不匹配")
+	wait, err := strconv.Atoi(strings.TrimSpace(cfg.Raw["test_wait"]))
+	if err != nil || wait < 0 {
+		wait = DEFAULT_TEST_WAIT
+	}
+	if now-test.Start < int64(wait) {
+		return test, events
 	}
 
-	byteCount := int(pdu[2])
-	if len(pdu) < 3+byteCount {
-		return nil, errf("响应数据长度不足")
+	var control map[string]json.RawMessage
+	json.Unmarshal([]byte(cfg.Raw["control"]), &control)
+	status, err := parseRegister(control, "test_status")
+	if err != nil {
+		test.Error = err.Error()
+		return test, events
+	}
+	result, err := parseRegister(control, "test_result")
+	if err != nil {
+		test.Error = err.Error()
+		return test, events
 	}
 
-	values := make([]uint16, byteCount/2)
-	for i := 0; i < len(values); i++ {
-		values[i] = binary.BigEndian.Uint16(pdu[3+i*2:])
+	devAddr := byte(cfg.DeviceAddress)
+	if status != nil {
+		v, err := status.read(client, devAddr, cfg.Debug)
+		if err != nil {
+			test.Error = "test_status: " + err.Error()
+			return test, events
+		}
+		if v>>status.Bit&1 != 0 {
+			// 自检仍在进行
+			return test, events
+		}
 	}
 
-	return values, nil
-}
+	rec := testRecord{Type: "battery_test", Start: test.Start, End: now}
+	if result != nil {
+		v, err := result.read(client, devAddr, cfg.Debug)
+		if err != nil {
+			test.Error = "test_result: " + err.Error()
+			return test, events
+		}
+		n := int(v)
+		rec.Value = &n
+		rec.Result, _ = point.Point{States: result.states}.StateLabel(float64(v))
+	}
 
-// =============================================================================
-// 【固定不变】工具函数
-// =============================================================================
+	test.Pending, test.Last = false, &rec
+	driver.SaveState(key, test)
+	return test, append(events, rec)
+}
 
-func getConfig() DriverConfig {
-	def := DriverConfig{DeviceAddress: 1, FuncName: "read"}
-	var envelope struct {
-		Config map[string]string `json:"config"`
+// parseRegister 解析 control 中名为 name 的读取寄存器，未配置时返回 nil
+func parseRegister(control map[string]json.RawMessage, name string) (*register, error) {
+	raw := control[name]
+	if raw == nil {
+		return nil, nil
 	}
-	if err := pdk.InputJSON(&envelope); err != nil {
-		return def
+	fail := func(msg string) (*register, error) {
+		return nil, errors.New("control." + name + ": " + msg)
 	}
-
-	cfg := def
-	if v := strings.TrimSpace(envelope.Config["device_address"]); v != "" {
-		if n, err := strconv.Atoi(v); err == nil {
-			cfg.DeviceAddress = n
-		}
+	var reg register
+	if err := json.Unmarshal(raw, &reg); err != nil {
+		return fail(err.Error())
 	}
-	if v := strings.TrimSpace(envelope.Config["func_name"]); v != "" {
-		cfg.FuncName = v
+	switch reg.FuncCode {
+	case 0:
+		reg.FuncCode = modbus.FuncReadHolding
+	case modbus.FuncReadHolding, modbus.FuncReadInput:
+	default:
+		return fail("func_code must be 3 or 4")
 	}
-	if v := strings.TrimSpace(envelope.Config["field_name"]); v != "" {
-		cfg.FieldName = v
+	if reg.Address == nil {
+		return fail("missing address")
 	}
-	if v := strings.TrimSpace(envelope.Config["value"]); v != "" {
-		cfg.Value = v
+	if reg.Bit > 15 {
+		return fail("bit must be 0~15")
 	}
-	return cfg
-}
-
-func formatFloat(val float64, decimals int) string {
-	return strconv.FormatFloat(val, 'f', decimals, 64)
+	states, err := point.ParseStates(reg.States)
+	if err != nil {
+		return fail(err.Error())
+	}
+	reg.states = states
+	return &reg, nil
 }
 
-type simpleErr string
-
-func (e simpleErr) Error() string { return string(e) }
-func errf(s string) error         { return simpleErr(s) }
-
-func outputJSON(v interface{}) {
-	b, _ := json.Marshal(v)
-	if len(b) == 0 {
-		b = []byte(`{"success":false,"error":"encode failed"}`)
+func (r *register) read(client *modbus.Client, devAddr byte, debug bool) (uint16, error) {
+	values, err := client.ReadRegisters(devAddr, r.FuncCode, *r.Address, 1)
+	if err != nil {
+		if debug {
+			driver.Logf("read fc=%d addr=%d err=%v", r.FuncCode, *r.Address, err)
+		}
+		return 0, err
 	}
-	pdk.Output(b)
+	return values[0], nil
 }
 
 func main() {}
-
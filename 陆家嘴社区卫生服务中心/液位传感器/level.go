@@ -4,9 +4,9 @@
 //
 // 设备点表:
 //   - 液位(level): FC=03(HOLDING_REGISTER), 地址=0x0000, 长度=2
-//     数据类型=int64, 读写=R, 表达式=(v-101665)/9800, 小数位=3
+//     数据类型=uint32, 读写=R, 表达式=(v-101665)/9800, 小数位=3
 //   - 温度(wtemp): FC=03(HOLDING_REGISTER), 地址=0x0002, 长度=1
-//     数据类型=int64, 读写=R, 表达式=v/100, 小数位=2
+//     数据类型=uint16, 读写=R, 表达式=v/100, 小数位=2
 //
 // Host 提供: serial_transceive
 //
@@ -14,12 +14,9 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
-	"strconv"
-	"strings"
-
-	pdk "github.com/extism/go-pdk"
+	"github.com/gonglijing/xunjiFsu/drvs/driver"
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+	"github.com/gonglijing/xunjiFsu/drvs/point"
 )
 
 // =============================================================================
@@ -29,86 +26,55 @@ import (
 //go:wasmimport extism:host/user serial_transceive
 func serial_transceive(wPtr uint64, wSize uint64, rPtr uint64, rCap uint64, timeoutMs uint64) uint64
 
-// =============================================================================
-// 【固定不变】配置结构（网关传入）
-// =============================================================================
-type DriverConfig struct {
-	DeviceAddress int    `json:"device_address"` // Modbus 从站地址
-	FuncName      string `json:"func_name"`      // "read" | "write"
-	FieldName     string `json:"field_name"`     // 可写字段名
-	Value         string `json:"value"`          // 写操作的值
-	Debug         bool   `json:"debug"`          // 调试模式
-}
-
 // =============================================================================
 // 【用户修改】驱动版本
 // =============================================================================
 const DriverVersion = "1.0.0"
 
-// =============================================================================
-// 【用户修改】点表定义
-// =============================================================================
-const (
-	// 寄存器地址定义
-	// 格式: RegisterName = Address // 说明
-	REG_LEVEL = 0x0000 // 液位寄存器(2个寄存器)
-	REG_WTEMP = 0x0002 // 温度寄存器
-
-	// 功能码定义
-	FUNC_CODE_READ = 0x03 // 读保持寄存器
-)
-
 // =============================================================================
 // 【用户修改】点表配置
 // =============================================================================
-var pointConfig = []PointConfig{
-	{Field: "level", Address: REG_LEVEL, Length: 2, Decimals: 3, RW: "R", Unit: "", Label: "液位"},
-	{Field: "wtemp", Address: REG_WTEMP, Length: 1, Decimals: 2, RW: "R", Unit: "°C", Label: "温度"},
+// 工程值 = 原始值 * Scale + Offset，Expr 保留点表中的原始表达式
+// 【自动生成】pointgen
+// 由 points.xlsx 生成，请勿手工修改；修改点表后执行 make points
+var pointConfig = []point.Point{
+	{Field: "level", FuncCode: modbus.FuncReadHolding, Address: 0, Length: 2, DataType: point.TypeUint32, Scale: 1.0 / 9800, Offset: -101665.0 / 9800, Expr: "(v-101665)/9800", Decimals: 3, RW: "R", Unit: "", Label: "液位"},
+	{Field: "wtemp", FuncCode: modbus.FuncReadHolding, Address: 2, Length: 1, DataType: point.TypeUint16, Scale: 0.01, Expr: "v/100", Decimals: 2, RW: "R", Unit: "°C", Label: "温度"},
 }
 
-// 点表配置结构
-type PointConfig struct {
-	Field    string // 字段名
-	Address  uint16 // 寄存器地址
-	Length   uint16 // 寄存器数量
-	Decimals int    // 有效小数位数
-	RW       string // 读写属性
-	Unit     string // 单位
-	Label    string // 显示标签
+// 读取分组
+var readBlocks = []modbus.Block{
+	{FuncCode: modbus.FuncReadHolding, Start: 0, Count: 3},
 }
 
+// 【自动生成】结束
+
 // =============================================================================
 // 【固定不变】驱动入口
 // =============================================================================
 //
 //go:wasmexport handle
 func handle() int32 {
-	defer func() {
-		if r := recover(); r != nil {
-			outputJSON(map[string]interface{}{"success": false, "error": "panic"})
-		}
-	}()
+	defer driver.Recover()
 
-	cfg := getConfig()
+	cfg := driver.GetConfig()
+	client := driver.NewRTUClient(serial_transceive, cfg.Debug)
 
-	points := readAllPoints(cfg.DeviceAddress, cfg.Debug)
+	points, errs := point.ReadPoints(client, byte(cfg.DeviceAddress), readBlocks, pointConfig)
 
-	outputJSON(map[string]interface{}{
-		"success": true,
-		"points":  points,
-	})
+	driver.OutputJSON(point.Result(points, errs))
 	return 0
 }
 
 // =============================================================================
-// 【固定不变】描述可写字段
+// 【固定不变】描述点表
 // =============================================================================
 //
 //go:wasmexport describe
 func describe() int32 {
-	outputJSON(map[string]interface{}{
+	driver.OutputJSON(map[string]interface{}{
 		"success": true,
-		"data":    map[string]string{},
+		"data":    point.Describe(DriverVersion, "modbus-rtu", pointConfig),
 	})
 	return 0
 }
@@ -119,270 +85,8 @@ func describe() int32 {
 //
 //go:wasmexport version
 func version() int32 {
-	outputJSON(map[string]interface{}{
-		"success": true,
-		"data": map[string]string{
-			"version": DriverVersion,
-		},
-	})
+	driver.OutputVersion(DriverVersion)
 	return 0
 }
 
-// =============================================================================
-// 【用户修改】读取所有测点
-// =============================================================================
-func readAllPoints(devAddr int, debug bool) []map[string]interface{} {
-	points := make([]map[string]interface{}, 0)
-
-	if len(pointConfig) == 0 {
-		return points
-	}
-
-	startAddr := pointConfig[0].Address
-	maxEndAddr := uint16(0)
-	for _, p := range pointConfig {
-		if p.Address < startAddr {
-			startAddr = p.Address
-		}
-		endAddr := p.Address + p.Length
-		if endAddr > maxEndAddr {
-			maxEndAddr = endAddr
-		}
-	}
-	totalLength := maxEndAddr - startAddr
-
-	req := buildReadFrame(byte(devAddr), startAddr, totalLength)
-	if debug {
-		logf("rtu req=% X", req)
-	}
-	resp, n := serialTransceive(req, int(totalLength)*2+5, 1000)
-	if debug {
-		logf("rtu n=%d resp=%s", n, hexPreview(resp, n, 16))
-	}
-	if n <= 0 {
-		return points
-	}
-
-	values, err := parseReadResponse(resp[:n], byte(devAddr))
-	if err != nil {
-		if debug {
-			logf("parse err=%v", err)
-		}
-		return points
-	}
-
-	for _, cfg := range pointConfig {
-		offset := int(cfg.Address - startAddr)
-		if offset < 0 || offset+int(cfg.Length) > len(values) {
-			continue
-		}
-
-		rawVal := combineRegisters(values[offset : offset+int(cfg.Length)])
-		realVal := applyExpression(cfg.Field, rawVal)
-
-		points = append(points, map[string]interface{}{
-			"field_name": cfg.Field,
-			"value":      formatFloat(realVal, cfg.Decimals),
-			"rw":         cfg.RW,
-			"unit":       cfg.Unit,
-			"label":      cfg.Label,
-		})
-	}
-
-	return points
-}
-
-func combineRegisters(words []uint16) int64 {
-	if len(words) == 0 {
-		return 0
-	}
-	if len(words) == 1 {
-		return int64(words[0])
-	}
-
-	var v uint32
-	for _, w := range words {
-		v = (v << 16) | uint32(w)
-	}
-	return int64(v)
-}
-
-func applyExpression(field string, raw int64) float64 {
-	v := float64(raw)
-	switch field {
-	case "level":
-		return (v - 101665.0) / 9800.0
-	case "wtemp":
-		return v / 100.0
-	default:
-		return v
-	}
-}
-
-// =============================================================================
-// 【固定不变】Modbus RTU 通信函数
-// =============================================================================
-
-func serialTransceive(req []byte, respLen int, timeoutMs int) ([]byte, int) {
-	if len(req) == 0 || respLen <= 0 {
-		return nil, 0
-	}
-
-	reqMem := pdk.AllocateBytes(req)
-	defer reqMem.Free()
-	respMem := pdk.Allocate(respLen)
-	defer respMem.Free()
-
-	n := int(serial_transceive(
-		reqMem.Offset(), uint64(len(req)),
-		respMem.Offset(), uint64(respLen),
-		uint64(timeoutMs),
-	))
-	if n <= 0 {
-		return nil, n
-	}
-	if n > respLen {
-		n = respLen
-	}
-
-	resp := make([]byte, n)
-	mem := pdk.NewMemory(respMem.Offset(), uint64(n))
-	mem.Load(resp)
-	return resp, n
-}
-
-func buildReadFrame(addr byte, start uint16, qty uint16) []byte {
-	req := make([]byte, 8)
-	req[0] = addr                                // 从站地址
-	req[1] = FUNC_CODE_READ                      // 功能码 0x03
-	req[2], req[3] = byte(start>>8), byte(start) // 起始地址
-	req[4], req[5] = byte(qty>>8), byte(qty)     // 寄存器数量
-	crc := crc16(req[:6])
-	req[6], req[7] = byte(crc), byte(crc>>8) // CRC 校验
-	return req
-}
-
-func parseReadResponse(data []byte, addr byte) ([]uint16, error) {
-	if len(data) < 5 || data[0] != addr || data[1] != FUNC_CODE_READ {
-		return nil, errf("invalid response")
-	}
-	byteCnt := int(data[2])
-	if byteCnt < 2 || len(data) < 3+byteCnt+2 {
-		return nil, errf("byte count mismatch")
-	}
-	if !checkCRC(data[:3+byteCnt+2]) {
-		return nil, errf("crc error")
-	}
-
-	values := make([]uint16, byteCnt/2)
-	for i := 0; i < len(values); i++ {
-		values[i] = uint16(data[3+i*2])<<8 | uint16(data[4+i*2])
-	}
-	return values, nil
-}
-
-func crc16(data []byte) uint16 {
-	var crc uint16 = 0xFFFF
-	for _, b := range data {
-		crc ^= uint16(b)
-		for i := 0; i < 8; i++ {
-			if crc&0x0001 != 0 {
-				crc = (crc >> 1) ^ 0xA001
-			} else {
-				crc >>= 1
-			}
-		}
-	}
-	return crc
-}
-
-func checkCRC(data []byte) bool {
-	if len(data) < 2 {
-		return false
-	}
-	got := uint16(data[len(data)-2]) | uint16(data[len(data)-1])<<8
-	return crc16(data[:len(data)-2]) == got
-}
-
-// =============================================================================
-// 【固定不变】工具函数
-// =============================================================================
-
-func getConfig() DriverConfig {
-	def := DriverConfig{DeviceAddress: 1, FuncName: "read"}
-	var envelope struct {
-		Config map[string]string `json:"config"`
-	}
-	if err := pdk.InputJSON(&envelope); err != nil {
-		return def
-	}
-
-	cfg := def
-	if v := strings.TrimSpace(envelope.Config["device_address"]); v != "" {
-		if n, err := strconv.Atoi(v); err == nil {
-			cfg.DeviceAddress = n
-		}
-	}
-	if v := strings.TrimSpace(envelope.Config["func_name"]); v != "" {
-		cfg.FuncName = v
-	}
-	if v := strings.TrimSpace(envelope.Config["field_name"]); v != "" {
-		cfg.FieldName = v
-	}
-	if v := strings.TrimSpace(envelope.Config["value"]); v != "" {
-		cfg.Value = v
-	}
-	if v := strings.TrimSpace(envelope.Config["debug"]); v != "" {
-		cfg.Debug = v == "1" || strings.EqualFold(v, "true")
-	}
-	return cfg
-}
-
-func formatFloat(val float64, decimals int) string {
-	return strconv.FormatFloat(val, 'f', decimals, 64)
-}
-
-type simpleErr string
-
-func (e simpleErr) Error() string { return string(e) }
-func errf(s string) error         { return simpleErr(s) }
-
-func outputJSON(v interface{}) {
-	b, _ := json.Marshal(v)
-	if len(b) == 0 {
-		b = []byte(`{"success":false,"error":"encode failed"}`)
-	}
-	pdk.Output(b)
-}
-
-func logf(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	pdk.Log(pdk.LogDebug, msg)
-}
-
-func hexPreview(b []byte, n int, max int) string {
-	if n <= 0 {
-		return ""
-	}
-	if n > len(b) {
-		n = len(b)
-	}
-	if n > max {
-		n = max
-	}
-	return fmt.Sprintf("% X", b[:n])
-}
-
-func appendEscaped(dst []byte, s string) []byte {
-	for i := 0; i < len(s); i++ {
-		switch s[i] {
-		case '\\', '"':
-			dst = append(dst, '\\', s[i])
-		default:
-			dst = append(dst, s[i])
-		}
-	}
-	return dst
-}
-
 func main() {}
@@ -3,28 +3,32 @@
 // =============================================================================
 //
 // 设备点表:
-//   - 温度设点(TEMSET): FC=03, 地址=0, 长度=1, 表达式=v/10
-//   - 湿度设点(HUMSET): FC=03, 地址=2, 长度=1, 表达式=v/10
+//   - 温度设点(TEMSET): FC=03/06/16, 地址=0, 长度=1, 表达式=v/10, 可写
+//   - 湿度设点(HUMSET): FC=03/06/16, 地址=2, 长度=1, 表达式=v/10, 可写
 //   - 环境温度(TEM): FC=03, 地址=48, 长度=1, 表达式=v/10
 //   - 环境湿度(HUM): FC=03, 地址=49, 长度=1, 表达式=v/10
-//   - 室内高温报警值(IHTAV): FC=03, 地址=17, 长度=1, 表达式=v/10
-//   - 室内低温报警值(ILTAV): FC=03, 地址=18, 长度=1, 表达式=v/10
-//   - 高湿度报警值(HHAV): FC=03, 地址=19, 长度=1, 表达式=v/10
-//   - 低湿度报警值(LHAV): FC=03, 地址=20, 长度=1, 表达式=v/10
+//   - 室内高温报警值(IHTAV): FC=03/06/16, 地址=17, 长度=1, 表达式=v/10, 可写
+//   - 室内低温报警值(ILTAV): FC=03/06/16, 地址=18, 长度=1, 表达式=v/10, 可写
+//   - 高湿度报警值(HHAV): FC=03/06/16, 地址=19, 长度=1, 表达式=v/10, 可写
+//   - 低湿度报警值(LHAV): FC=03/06/16, 地址=20, 长度=1, 表达式=v/10, 可写
 //   - 设备地址(ADD): FC=03, 地址=94, 长度=1, 表达式=v
 //
+// 写操作: func_name=write, field_name=<可写字段>, value=<工程值>
+//   - 默认 FC06 写单个寄存器，从站返回非法功能(01)时改用 FC16 重试
+//   - 配置 write_func=16 可直接使用 FC16
+//
 // Host 提供: serial_transceive
 //
 // =============================================================================
 package main
 
 import (
-	"encoding/json"
-	"fmt"
 	"strconv"
 	"strings"
 
-	pdk "github.com/extism/go-pdk"
+	"github.com/gonglijing/xunjiFsu/drvs/driver"
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+	"github.com/gonglijing/xunjiFsu/drvs/point"
 )
 
 // =============================================================================
@@ -34,41 +38,43 @@ import (
 //go:wasmimport extism:host/user serial_transceive
 func serial_transceive(wPtr uint64, wSize uint64, rPtr uint64, rCap uint64, timeoutMs uint64) uint64
 
-// =============================================================================
-// 【固定不变】配置结构（网关传入）
-// =============================================================================
-type DriverConfig struct {
-	DeviceAddress int    `json:"device_address"`
-	FuncName      string `json:"func_name"`
-	FieldName     string `json:"field_name"`
-	Value         string `json:"value"`
-	Debug         bool   `json:"debug"`
-}
-
 // =============================================================================
 // 【用户修改】驱动版本
 // =============================================================================
 const DriverVersion = "1.0.0"
 
 // =============================================================================
-// 【用户修改】点表定义
+// 【用户修改】点表配置
 // =============================================================================
-const (
-	REG_TEMSET = 0
-	REG_HUMSET = 2
-
-	REG_IHTAV = 17
-	REG_ILTAV = 18
-	REG_HHAV  = 19
-	REG_LHAV  = 20
+// RW 为 "RW" 的测点可通过 func_name=write 写入，写入值按 Scale 反算
+// 【自动生成】pointgen -gap 1
+// 由 points.xlsx 生成，请勿手工修改；修改点表后执行 make points
+var pointConfig = []point.Point{
+	{Field: "TEMSET", FuncCode: modbus.FuncReadHolding, Address: 0, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "RW", Unit: "℃", Label: "温度设点"},
+	{Field: "HUMSET", FuncCode: modbus.FuncReadHolding, Address: 2, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "RW", Unit: "%", Label: "湿度设点", Min: 0, Max: 100},
+	{Field: "TEM", FuncCode: modbus.FuncReadHolding, Address: 48, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "R", Unit: "℃", Label: "环境温度"},
+	{Field: "HUM", FuncCode: modbus.FuncReadHolding, Address: 49, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "R", Unit: "%", Label: "环境湿度", Min: 0, Max: 100},
+	{Field: "IHTAV", FuncCode: modbus.FuncReadHolding, Address: 17, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "RW", Unit: "℃", Label: "室内高温报警值"},
+	{Field: "ILTAV", FuncCode: modbus.FuncReadHolding, Address: 18, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "RW", Unit: "℃", Label: "室内低温报警值"},
+	{Field: "HHAV", FuncCode: modbus.FuncReadHolding, Address: 19, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "RW", Unit: "%", Label: "高湿度报警值", Min: 0, Max: 100},
+	{Field: "LHAV", FuncCode: modbus.FuncReadHolding, Address: 20, Length: 1, DataType: point.TypeInt16, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "RW", Unit: "%", Label: "低湿度报警值", Min: 0, Max: 100},
+	{Field: "ADD", FuncCode: modbus.FuncReadHolding, Address: 94, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 1, RW: "R", Unit: "", Label: "设备地址"},
+}
 
-	REG_TEM = 48
-	REG_HUM = 49
+// 读取分组
+var readBlocks = []modbus.Block{
+	{FuncCode: modbus.FuncReadHolding, Start: 0, Count: 3},
+	{FuncCode: modbus.FuncReadHolding, Start: 17, Count: 4},
+	{FuncCode: modbus.FuncReadHolding, Start: 48, Count: 2},
+	{FuncCode: modbus.FuncReadHolding, Start: 94, Count: 1},
+}
 
-	REG_ADD = 94
+// 【自动生成】结束
 
-	FUNC_CODE_READ = 0x03
-)
+// write_func 配置项
+var configKeys = []point.ConfigKey{
+	{Key: "write_func", Type: "string", Default: "6", Desc: "写功能码 6 | 16"},
+}
 
 // =============================================================================
 // 【固定不变】驱动入口
@@ -76,31 +82,31 @@ const (
 //
 //go:wasmexport handle
 func handle() int32 {
-	defer func() {
-		if r := recover(); r != nil {
-			outputJSON(map[string]interface{}{"success": false, "error": "panic"})
-		}
-	}()
+	defer driver.Recover()
 
-	cfg := getConfig()
-	points := readAllPoints(cfg.DeviceAddress, cfg.Debug)
+	cfg := driver.GetConfig()
+	client := driver.NewRTUClient(serial_transceive, cfg.Debug)
 
-	outputJSON(map[string]interface{}{
-		"success": true,
-		"points":  points,
-	})
+	if strings.EqualFold(cfg.FuncName, "write") {
+		driver.OutputJSON(writePoint(client, byte(cfg.DeviceAddress), cfg))
+		return 0
+	}
+
+	points, errs := point.ReadPoints(client, byte(cfg.DeviceAddress), readBlocks, pointConfig)
+
+	driver.OutputJSON(point.Result(points, errs))
 	return 0
 }
 
 // =============================================================================
-// 【固定不变】描述可写字段
+// 【固定不变】描述点表
 // =============================================================================
 //
 //go:wasmexport describe
 func describe() int32 {
-	outputJSON(map[string]interface{}{
+	driver.OutputJSON(map[string]interface{}{
 		"success": true,
-		"data":    map[string]string{},
+		"data":    point.Describe(DriverVersion, "modbus-rtu", pointConfig, configKeys...),
 	})
 	return 0
 }
@@ -111,250 +117,63 @@ func describe() int32 {
 //
 //go:wasmexport version
 func version() int32 {
-	outputJSON(map[string]interface{}{
-		"success": true,
-		"data": map[string]string{
-			"version": DriverVersion,
-		},
-	})
+	driver.OutputVersion(DriverVersion)
 	return 0
 }
 
 // =============================================================================
-// 【用户修改】读取所有测点
-// =============================================================================
-func readAllPoints(devAddr int, debug bool) []map[string]interface{} {
-	points := make([]map[string]interface{}, 0)
-
-	if values := readMultipleRegs(byte(devAddr), REG_TEMSET, 3, debug); values != nil {
-		points = append(points, makePoint("TEMSET", int(values[0]), 0.1, 1, "R", "℃", "温度设点"))
-		points = append(points, makePoint("HUMSET", int(values[2]), 0.1, 1, "R", "%", "湿度设点"))
-	}
-
-	if values := readMultipleRegs(byte(devAddr), REG_IHTAV, 4, debug); values != nil {
-		points = append(points, makePoint("IHTAV", int(values[0]), 0.1, 1, "R", "℃", "室内高温报警值"))
-		points = append(points, makePoint("ILTAV", int(values[1]), 0.1, 1, "R", "℃", "室内低温报警值"))
-		points = append(points, makePoint("HHAV", int(values[2]), 0.1, 1, "R", "%", "高湿度报警值"))
-		points = append(points, makePoint("LHAV", int(values[3]), 0.1, 1, "R", "%", "低湿度报警值"))
-	}
-
-	if values := readMultipleRegs(byte(devAddr), REG_TEM, 2, debug); values != nil {
-		points = append(points, makePoint("TEM", int(values[0]), 0.1, 1, "R", "℃", "环境温度"))
-		points = append(points, makePoint("HUM", int(values[1]), 0.1, 1, "R", "%", "环境湿度"))
-	}
-
-	if val := readSingleReg(byte(devAddr), REG_ADD, debug); val >= 0 {
-		points = append(points, makePoint("ADD", int(val), 1, 1, "R", "", "设备地址"))
-	}
-
-	return points
-}
-
-func makePoint(field string, rawVal int, scale float64, decimals int, rw, unit, label string) map[string]interface{} {
-	realVal := float64(rawVal) * scale
-	return makePointValue(field, realVal, decimals, rw, unit, label)
-}
-
-func makePointValue(field string, value float64, decimals int, rw, unit, label string) map[string]interface{} {
-	return map[string]interface{}{
-		"field_name": field,
-		"value":      formatFloat(value, decimals),
-		"rw":         rw,
-		"unit":       unit,
-		"label":      label,
-	}
-}
-
-// =============================================================================
-// 【固定不变】Modbus RTU 通信函数
+// 【用户修改】写入测点
 // =============================================================================
-
-func readSingleReg(devAddr byte, regAddr uint16, debug bool) int {
-	values := readMultipleRegs(devAddr, regAddr, 1, debug)
-	if values == nil || len(values) < 1 {
-		return -1
-	}
-	return int(values[0])
-}
-
-func readMultipleRegs(devAddr byte, startReg uint16, count uint16, debug bool) []int16 {
-	req := buildReadFrame(devAddr, startReg, count)
-	if debug {
-		logf("rtu req=% X", req)
-	}
-
-	resp, n := serialTransceive(req, int(count)*2+5, 1000)
-	if debug {
-		logf("rtu n=%d resp=%s", n, hexPreview(resp, n, 24))
-	}
-	if n <= 0 {
-		return nil
-	}
-
-	values, err := parseReadResponse(resp[:n], devAddr)
-	if err != nil || len(values) < int(count) {
-		if debug {
-			logf("parse err=%v", err)
-		}
-		return nil
-	}
-
-	result := make([]int16, count)
-	for i := 0; i < int(count); i++ {
-		result[i] = int16(values[i])
-	}
-	return result
-}
-
-func serialTransceive(req []byte, respLen int, timeoutMs int) ([]byte, int) {
-	if len(req) == 0 || respLen <= 0 {
-		return nil, 0
-	}
-
-	reqMem := pdk.AllocateBytes(req)
-	defer reqMem.Free()
-	respMem := pdk.Allocate(respLen)
-	defer respMem.Free()
-
-	n := int(serial_transceive(
-		reqMem.Offset(), uint64(len(req)),
-		respMem.Offset(), uint64(respLen),
-		uint64(timeoutMs),
-	))
-	if n <= 0 {
-		return nil, n
-	}
-	if n > respLen {
-		n = respLen
+func writePoint(client *modbus.Client, devAddr byte, cfg driver.Config) map[string]interface{} {
+	wp, ok := point.Find(pointConfig, cfg.FieldName)
+	if !ok || !wp.Writable() {
+		return map[string]interface{}{"success": false, "error": "field not writable: " + cfg.FieldName}
 	}
 
-	resp := make([]byte, n)
-	mem := pdk.NewMemory(respMem.Offset(), uint64(n))
-	mem.Load(resp)
-	return resp, n
-}
-
-func buildReadFrame(addr byte, start uint16, qty uint16) []byte {
-	req := make([]byte, 8)
-	req[0] = addr
-	req[1] = FUNC_CODE_READ
-	req[2], req[3] = byte(start>>8), byte(start)
-	req[4], req[5] = byte(qty>>8), byte(qty)
-	crc := crc16(req[:6])
-	req[6], req[7] = byte(crc), byte(crc>>8)
-	return req
-}
-
-func parseReadResponse(data []byte, addr byte) ([]uint16, error) {
-	if len(data) < 5 || data[0] != addr || data[1] != FUNC_CODE_READ {
-		return nil, errf("invalid response")
-	}
-	byteCnt := int(data[2])
-	if byteCnt < 2 || len(data) < 3+byteCnt+2 {
-		return nil, errf("byte count mismatch")
+	value, err := strconv.ParseFloat(strings.TrimSpace(cfg.Value), 64)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": "invalid value: " + cfg.Value}
 	}
-	if !checkCRC(data[:3+byteCnt+2]) {
-		return nil, errf("crc error")
+	lo, hi := wp.Range()
+	if value < lo || value > hi {
+		return map[string]interface{}{"success": false, "error": "value out of range: " + cfg.Value}
 	}
+	raw := wp.Inverse(value)
+	regVal := uint16(int16(raw))
 
-	values := make([]uint16, byteCnt/2)
-	for i := 0; i < len(values); i++ {
-		values[i] = uint16(data[3+i*2])<<8 | uint16(data[4+i*2])
+	funcCode := byte(modbus.FuncWriteSingle)
+	if v := strings.TrimSpace(cfg.Raw["write_func"]); v == "16" || strings.EqualFold(v, "0x10") {
+		funcCode = modbus.FuncWriteMultiple
 	}
-	return values, nil
-}
 
-func crc16(data []byte) uint16 {
-	var crc uint16 = 0xFFFF
-	for _, b := range data {
-		crc ^= uint16(b)
-		for i := 0; i < 8; i++ {
-			if crc&0x0001 != 0 {
-				crc = (crc >> 1) ^ 0xA001
-			} else {
-				crc >>= 1
+	if funcCode == modbus.FuncWriteSingle {
+		err = client.WriteSingleRegister(devAddr, wp.Address, regVal)
+		if ex, ok := modbus.AsException(err); ok && ex.Code == modbus.ExceptionIllegalFunction {
+			if cfg.Debug {
+				driver.Logf("fc06 rejected, retry with fc16 addr=%d", wp.Address)
 			}
+			funcCode = modbus.FuncWriteMultiple
 		}
 	}
-	return crc
-}
-
-func checkCRC(data []byte) bool {
-	if len(data) < 2 {
-		return false
+	if funcCode == modbus.FuncWriteMultiple {
+		err = client.WriteMultipleRegisters(devAddr, wp.Address, []uint16{regVal})
 	}
-	got := uint16(data[len(data)-2]) | uint16(data[len(data)-1])<<8
-	return crc16(data[:len(data)-2]) == got
-}
-
-// =============================================================================
-// 【固定不变】工具函数
-// =============================================================================
 
-func getConfig() DriverConfig {
-	def := DriverConfig{DeviceAddress: 1, FuncName: "read"}
-	var envelope struct {
-		Config map[string]string `json:"config"`
-	}
-	if err := pdk.InputJSON(&envelope); err != nil {
-		return def
+	data := map[string]interface{}{
+		"field_name": cfg.FieldName,
+		"value":      wp.Format(wp.Convert(raw)),
+		"unit":       wp.Unit,
+		"raw":        int(raw),
+		"func_code":  int(funcCode),
+		"accepted":   err == nil,
 	}
-
-	cfg := def
-	if v := strings.TrimSpace(envelope.Config["device_address"]); v != "" {
-		if n, err := strconv.Atoi(v); err == nil {
-			cfg.DeviceAddress = n
+	if err != nil {
+		if ex, ok := modbus.AsException(err); ok {
+			data["exception_code"] = int(ex.Code)
 		}
+		return map[string]interface{}{"success": false, "error": err.Error(), "data": data}
 	}
-	if v := strings.TrimSpace(envelope.Config["func_name"]); v != "" {
-		cfg.FuncName = v
-	}
-	if v := strings.TrimSpace(envelope.Config["field_name"]); v != "" {
-		cfg.FieldName = v
-	}
-	if v := strings.TrimSpace(envelope.Config["value"]); v != "" {
-		cfg.Value = v
-	}
-	if v := strings.TrimSpace(envelope.Config["debug"]); v != "" {
-		cfg.Debug = v == "1" || strings.EqualFold(v, "true")
-	}
-	return cfg
-}
-
-func formatFloat(val float64, decimals int) string {
-	return strconv.FormatFloat(val, 'f', decimals, 64)
-}
-
-type simpleErr string
-
-func (e simpleErr) Error() string { return string(e) }
-func errf(s string) error         { return simpleErr(s) }
-
-func outputJSON(v interface{}) {
-	b, _ := json.Marshal(v)
-	if len(b) == 0 {
-		b = []byte(`{"success":false,"error":"encode failed"}`)
-	}
-	pdk.Output(b)
-}
-
-func logf(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	pdk.Log(pdk.LogDebug, msg)
-}
-
-func hexPreview(b []byte, n int, max int) string {
-	if n <= 0 {
-		return ""
-	}
-	if n > len(b) {
-		n = len(b)
-	}
-	if n > max {
-		n = max
-	}
-	return fmt.Sprintf("% X", b[:n])
+	return map[string]interface{}{"success": true, "data": data}
 }
 
 func main() {}
-
@@ -11,6 +11,13 @@
 //   - 功能码: 0x03 (HOLDING_REGISTER)
 //   - 原始连续地址段: 257~416, 513~627
 //   - 读取分片(每次<=50寄存器): 257+50, 307+50, 357+50, 407+10, 513+50, 563+50, 613+15
+//   - 点表由 points.xlsx 经 pointgen 生成
+//   - 寻址方式、功能码与不可读寄存器段保存在 Extism var（read_plan@<从站地址>），见 readPlan
+//   - 各测点上次取值保存在 Extism var（point_states@<从站地址>），状态变化输出为 events
+//   - 面板状态码含义未经核对，点表不配置；现场核对后由 config.states 给出
+//   - 楼层/区域/设备类别由标签与字段名前缀推导（tags），按楼层与类别汇总（summary）
+//   - 面板原生串口/CRT 协议不支持（无协议文档），须经 Modbus 转换卡接入，见 README
+//   - func_name=reset|silence|isolate|enable 按 config.control 下发控制命令（FC05/FC06），须带 confirm，屏蔽/启用缺省读回核对
 //
 // Host 提供: serial_transceive
 //
@@ -18,644 +25,857 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
+	"errors"
 	"strconv"
 	"strings"
 
-	pdk "github.com/extism/go-pdk"
+	"github.com/gonglijing/xunjiFsu/drvs/driver"
+	"github.com/gonglijing/xunjiFsu/drvs/fire"
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+	"github.com/gonglijing/xunjiFsu/drvs/point"
 )
 
 //go:wasmimport extism:host/user serial_transceive
 func serial_transceive(wPtr uint64, wSize uint64, rPtr uint64, rCap uint64, timeoutMs uint64) uint64
 
-type DriverConfig struct {
-	DeviceAddress int    `json:"device_address"`
-	FuncName      string `json:"func_name"`
-	FieldName     string `json:"field_name"`
-	Value         string `json:"value"`
-	Debug         bool   `json:"debug"`
-}
-
 const DriverVersion = "1.0.0"
 
 const (
-	FUNC_CODE_READ_HOLDING = 0x03
-	FUNC_CODE_READ_INPUT   = 0x04
+	FUNC_CODE_READ_HOLDING = modbus.FuncReadHolding
+	FUNC_CODE_READ_INPUT   = modbus.FuncReadInput
 )
 
-type PointConfig struct {
-	Field    string
-	Address  uint16
-	Scale    float64
-	Decimals int
-	RW       string
-	Unit     string
-	Label    string
-}
-
-var pointConfig = []PointConfig{
-	{Field: "yg0101", Address: 257, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层心电烟感"},
-	{Field: "yg0102", Address: 258, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层心电烟感"},
-	{Field: "yg0103", Address: 259, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层外科烟感"},
-	{Field: "yg0104", Address: 260, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层外科烟感"},
-	{Field: "yg0105", Address: 261, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层外科烟感"},
-	{Field: "yg0106", Address: 262, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层B超烟感"},
-	{Field: "yg0107", Address: 263, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层楼梯口烟感"},
-	{Field: "yg0108", Address: 264, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层楼梯口烟感"},
-	{Field: "yg0109", Address: 265, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层化验科烟感"},
-	{Field: "yg010a", Address: 266, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层化验科烟感"},
-	{Field: "yg010b", Address: 267, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层化验科烟感"},
-	{Field: "yg010c", Address: 268, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层化验科烟感"},
-	{Field: "yg010d", Address: 269, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层化验科烟感"},
-	{Field: "yg010e", Address: 270, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层化验科烟感"},
-	{Field: "yg010f", Address: 271, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层化验科烟感"},
-	{Field: "yg0110", Address: 272, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层全科诊室烟感"},
-	{Field: "yg0111", Address: 273, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层全科诊室烟感"},
-	{Field: "yg0112", Address: 274, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层全科诊室烟感"},
-	{Field: "yg0113", Address: 275, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层全科诊室烟感"},
-	{Field: "yg0114", Address: 276, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层全科诊室烟感"},
-	{Field: "yg0115", Address: 277, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层全科诊室烟感"},
-	{Field: "yg0116", Address: 278, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层全科诊室烟感"},
-	{Field: "yg0117", Address: 279, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层大门口烟感"},
-	{Field: "yg0118", Address: 280, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层更衣室烟感"},
-	{Field: "yg0119", Address: 281, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层收费处烟感"},
-	{Field: "yg011a", Address: 282, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层五官科烟感"},
-	{Field: "yg011b", Address: 283, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层五官科烟感"},
-	{Field: "yg011c", Address: 284, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层五官科烟感"},
-	{Field: "yg011d", Address: 285, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层垃圾房烟感"},
-	{Field: "yg011e", Address: 286, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层补液大厅烟感"},
-	{Field: "yg011f", Address: 287, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层注射室烟感"},
-	{Field: "yg0120", Address: 288, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层配电室烟感"},
-	{Field: "yg0121", Address: 289, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层补液大厅烟感"},
-	{Field: "yg0122", Address: 290, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层补液大厅烟感"},
-	{Field: "yg0123", Address: 291, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层抢救室烟感"},
-	{Field: "yg0124", Address: 292, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层配电室烟感"},
-	{Field: "yg0125", Address: 293, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
-	{Field: "yg0126", Address: 294, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
-	{Field: "yg0127", Address: 295, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层药库烟感"},
-	{Field: "yg0128", Address: 296, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层药库烟感"},
-	{Field: "yg0129", Address: 297, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层药库烟感"},
-	{Field: "yg012a", Address: 298, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
-	{Field: "yg012b", Address: 299, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
-	{Field: "yg012c", Address: 300, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
-	{Field: "yg012d", Address: 301, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
-	{Field: "yg012e", Address: 302, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
-	{Field: "yg012f", Address: 303, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
-	{Field: "yg0130", Address: 304, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
-	{Field: "yg0131", Address: 305, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
-	{Field: "sb0132", Address: 306, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道手报"},
-	{Field: "sb0133", Address: 307, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道手报"},
-	{Field: "sb0134", Address: 308, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道手报"},
-	{Field: "xb0135", Address: 309, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道消报"},
-	{Field: "xb0136", Address: 310, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道消报"},
-	{Field: "xb0137", Address: 311, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层消报"},
-	{Field: "xb0138", Address: 312, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层消报"},
-	{Field: "slzs0139", Address: 313, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层水流指示"},
-	{Field: "xhf013a", Address: 314, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层信号阀"},
-	{Field: "sg013b", Address: 315, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层声光"},
-	{Field: "sg013c", Address: 316, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层声光"},
-	{Field: "sg013d", Address: 317, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层声光"},
-	{Field: "yg013e", Address: 318, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中医诊室烟感"},
-	{Field: "yg013f", Address: 319, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中医诊室烟感"},
-	{Field: "yg0140", Address: 320, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中医诊室烟感"},
-	{Field: "yg0141", Address: 321, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层楼梯口烟感"},
-	{Field: "yg0142", Address: 322, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中医诊室烟感"},
-	{Field: "yg0143", Address: 323, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中医诊室烟感"},
-	{Field: "yg0144", Address: 324, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中医诊室烟感"},
-	{Field: "yg0145", Address: 325, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中医诊室烟感"},
-	{Field: "yg0146", Address: 326, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层口腔科烟感"},
-	{Field: "yg0147", Address: 327, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层口腔科烟感"},
-	{Field: "yg0148", Address: 328, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层口腔科烟感"},
-	{Field: "yg0149", Address: 329, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层口腔科烟感"},
-	{Field: "yg014a", Address: 330, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层机房烟感"},
-	{Field: "yg014b", Address: 331, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层楼梯口烟感"},
-	{Field: "yg014c", Address: 332, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层康复诊疗室烟感"},
-	{Field: "yg014d", Address: 333, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层康复诊疗室烟感"},
-	{Field: "yg014e", Address: 334, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层全科诊室烟感"},
-	{Field: "yg014f", Address: 335, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层心电图室烟感"},
-	{Field: "yg0150", Address: 336, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层医生办公室烟感"},
-	{Field: "yg0151", Address: 337, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层医生办公室烟感"},
-	{Field: "yg0152", Address: 338, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层医生办公室烟感"},
-	{Field: "yg0153", Address: 339, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中药房烟感"},
-	{Field: "yg0154", Address: 340, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层收费烟感"},
-	{Field: "yg0155", Address: 341, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层楼梯口烟感"},
-	{Field: "yg0156", Address: 342, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
-	{Field: "yg0157", Address: 343, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
-	{Field: "yg0158", Address: 344, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
-	{Field: "yg0159", Address: 345, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
-	{Field: "wg015a", Address: 346, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中医便诊室温感"},
-	{Field: "wg015b", Address: 347, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中医便诊室温感"},
-	{Field: "wg015c", Address: 348, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中医便诊室温感"},
-	{Field: "wg015d", Address: 349, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中医便诊室温感"},
-	{Field: "wg015e", Address: 350, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中医便诊室温感"},
-	{Field: "wg015f", Address: 351, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中医便诊室温感"},
-	{Field: "wg0160", Address: 352, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层中医便诊室温感"},
-	{Field: "sb0161", Address: 353, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道手报"},
-	{Field: "sb0162", Address: 354, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道手报"},
-	{Field: "sb0163", Address: 355, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道手报"},
-	{Field: "xb0164", Address: 356, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道消报"},
-	{Field: "xb0165", Address: 357, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道消报"},
-	{Field: "xb0166", Address: 358, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道消报"},
-	{Field: "xb0167", Address: 359, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道消报"},
-	{Field: "xhf0168", Address: 360, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层信号阀"},
-	{Field: "lszs0169", Address: 361, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层水流指示"},
-	{Field: "sg016a", Address: 362, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层声光"},
-	{Field: "sg016b", Address: 363, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层声光"},
-	{Field: "sg016c", Address: 364, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层声光"},
-	{Field: "yg016d", Address: 365, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层杂物间烟感"},
-	{Field: "yg016e", Address: 366, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层档案室烟感"},
-	{Field: "yg016f", Address: 367, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层档案室烟感"},
-	{Field: "yg0170", Address: 368, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层档案室烟感"},
-	{Field: "yg0171", Address: 369, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层杂物间烟感"},
-	{Field: "yg0172", Address: 370, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层餐厅烟感"},
-	{Field: "yg0173", Address: 371, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层餐厅烟感"},
-	{Field: "yg0174", Address: 372, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层餐厅烟感"},
-	{Field: "yg0175", Address: 373, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层摄片机房烟感"},
-	{Field: "yg0176", Address: 374, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层摄片机房烟感"},
-	{Field: "yg0177", Address: 375, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层摄片机房烟感"},
-	{Field: "yg0178", Address: 376, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层机房控制室烟感"},
-	{Field: "yg0179", Address: 377, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层候诊室烟感"},
-	{Field: "yg017a", Address: 378, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层登记室烟感"},
-	{Field: "yg017b", Address: 379, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层机房控制室烟感"},
-	{Field: "yg017c", Address: 380, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层透视机房烟感"},
-	{Field: "wg017d", Address: 381, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层厨房温感"},
-	{Field: "wg017e", Address: 382, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层厨房温感"},
-	{Field: "wg017f", Address: 383, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层厨房温感"},
-	{Field: "xb0180", Address: 384, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层候诊室消报"},
-	{Field: "xb0181", Address: 385, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层候诊室消报"},
-	{Field: "xb0182", Address: 386, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道消报"},
-	{Field: "xb0183", Address: 387, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道消报"},
-	{Field: "xb0184", Address: 388, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道消报"},
-	{Field: "xhf0185", Address: 389, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道信号阀"},
-	{Field: "slzs0186", Address: 390, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道水流指示"},
-	{Field: "sg0187", Address: 391, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "1层走道声光"},
-	{Field: "yg0188", Address: 392, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层办公室烟感"},
-	{Field: "yg0189", Address: 393, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层办公室烟感"},
-	{Field: "yg018a", Address: 394, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层测智室烟感"},
-	{Field: "yg018b", Address: 395, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层测智室烟感"},
-	{Field: "yg018c", Address: 396, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层儿科室烟感"},
-	{Field: "yg018d", Address: 397, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层计划免疫烟感"},
-	{Field: "yg018e", Address: 398, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层计划免疫烟感"},
-	{Field: "yg018f", Address: 399, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层计划免疫烟感"},
-	{Field: "yg0190", Address: 400, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层冷库烟感"},
-	{Field: "yg0191", Address: 401, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层215房间烟感"},
-	{Field: "yg0192", Address: 402, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层216房间烟感"},
-	{Field: "yg0193", Address: 403, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层217房间烟感"},
-	{Field: "yg0194", Address: 404, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层母婴室烟感"},
-	{Field: "yg0195", Address: 405, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
-	{Field: "yg0196", Address: 406, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
-	{Field: "yg0197", Address: 407, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
-	{Field: "yg0198", Address: 408, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
-	{Field: "sb0199", Address: 409, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道手报"},
-	{Field: "sb019a", Address: 410, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道手报"},
-	{Field: "xb019b", Address: 411, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道消报"},
-	{Field: "xb019c", Address: 412, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道消报"},
-	{Field: "xhdf019d", Address: 413, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道信号蝶阀"},
-	{Field: "slzs019e", Address: 414, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道水流指示"},
-	{Field: "sg019f", Address: 415, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道声光"},
-	{Field: "sg01a0", Address: 416, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "2层走道声光"},
-	{Field: "yg0201", Address: 513, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层院长办公室烟感"},
-	{Field: "yg0202", Address: 514, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层副院长办公室烟感"},
-	{Field: "yg0203", Address: 515, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层示范性教育室烟感"},
-	{Field: "yg0204", Address: 516, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层档案室烟感"},
-	{Field: "yg0205", Address: 517, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层人事部烟感"},
-	{Field: "yg0206", Address: 518, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层党支部烟感"},
-	{Field: "yg0207", Address: 519, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层办公室烟感"},
-	{Field: "yg0208", Address: 520, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层医疗办公室烟感"},
-	{Field: "yg0209", Address: 521, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层财务室烟感"},
-	{Field: "yg020a", Address: 522, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层楼梯口烟感"},
-	{Field: "yg020b", Address: 523, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层电梯机房烟感"},
-	{Field: "yg020c", Address: 524, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层仓库烟感"},
-	{Field: "yg020d", Address: 525, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层会议室烟感"},
-	{Field: "yg020e", Address: 526, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层会议室烟感"},
-	{Field: "yg020f", Address: 527, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层楼梯口烟感"},
-	{Field: "yg0210", Address: 528, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层医生值班室烟感"},
-	{Field: "yg0211", Address: 529, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层走道烟感"},
-	{Field: "yg0212", Address: 530, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层走道烟感"},
-	{Field: "yg0213", Address: 531, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层走道烟感"},
-	{Field: "yg0214", Address: 532, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层走道烟感"},
-	{Field: "sb0215", Address: 533, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层走道手报"},
-	{Field: "sg0216", Address: 534, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层走道声光"},
-	{Field: "sb0217", Address: 535, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层走道手报"},
-	{Field: "sg0218", Address: 536, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层走道声光"},
-	{Field: "xb0219", Address: 537, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层走道消报"},
-	{Field: "xb021a", Address: 538, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层走道消报"},
-	{Field: "sl021b", Address: 539, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层走道水流"},
-	{Field: "xhf021c", Address: 540, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "5层走道信号阀"},
-	{Field: "yg021d", Address: 541, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层储物间烟感"},
-	{Field: "yg021e", Address: 542, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
-	{Field: "yg021f", Address: 543, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
-	{Field: "yg0220", Address: 544, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层楼梯口烟感"},
-	{Field: "yg0221", Address: 545, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层vct室烟感"},
-	{Field: "yg0222", Address: 546, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层信息管理烟感"},
-	{Field: "yg0223", Address: 547, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层楼梯口烟感"},
-	{Field: "yg0224", Address: 548, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
-	{Field: "yg0225", Address: 549, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
-	{Field: "yg0226", Address: 550, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
-	{Field: "yg0227", Address: 551, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
-	{Field: "yg0228", Address: 552, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
-	{Field: "yg0229", Address: 553, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
-	{Field: "yg022a", Address: 554, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
-	{Field: "yg022b", Address: 555, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
-	{Field: "yg022c", Address: 556, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
-	{Field: "yg022d", Address: 557, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
-	{Field: "yg022e", Address: 558, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
-	{Field: "yg022f", Address: 559, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
-	{Field: "yg0230", Address: 560, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
-	{Field: "yg0231", Address: 561, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
-	{Field: "yg0232", Address: 562, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层杂物间烟感"},
-	{Field: "yg0233", Address: 563, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
-	{Field: "yg0234", Address: 564, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
-	{Field: "yg0235", Address: 565, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
-	{Field: "yg0236", Address: 566, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
-	{Field: "yg0237", Address: 567, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
-	{Field: "yg0238", Address: 568, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
-	{Field: "yg0239", Address: 569, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层走道烟感"},
-	{Field: "yg023a", Address: 570, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层走道烟感"},
-	{Field: "yg023b", Address: 571, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层走道烟感"},
-	{Field: "yg023c", Address: 572, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层走道烟感"},
-	{Field: "sb023d", Address: 573, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层走道手报"},
-	{Field: "sb023e", Address: 574, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层走道手报"},
-	{Field: "xb023f", Address: 575, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层走道消报"},
-	{Field: "xb0240", Address: 576, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层走道消报"},
-	{Field: "xb0241", Address: 577, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层走道消报"},
-	{Field: "sl0242", Address: 578, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层走道水流"},
-	{Field: "sl0243", Address: 579, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层走道水流"},
-	{Field: "sg0244", Address: 580, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层走道声光"},
-	{Field: "sg0245", Address: 581, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "4层走道声光"},
-	{Field: "yg0246", Address: 582, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg0247", Address: 583, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg0248", Address: 584, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg0249", Address: 585, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg024a", Address: 586, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg024b", Address: 587, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg024c", Address: 588, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg024d", Address: 589, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg024e", Address: 590, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层治疗准备室烟感"},
-	{Field: "yg024f", Address: 591, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层护士休息室烟感"},
-	{Field: "yg0250", Address: 592, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层护士办公室烟感"},
-	{Field: "yg0251", Address: 593, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层康复教室烟感"},
-	{Field: "yg0252", Address: 594, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道烟感"},
-	{Field: "yg0253", Address: 595, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层医生值班室烟感"},
-	{Field: "yg0254", Address: 596, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层卫生间烟感"},
-	{Field: "yg0255", Address: 597, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层医生办公室烟感"},
-	{Field: "yg0256", Address: 598, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层医生办公室烟感"},
-	{Field: "yg0257", Address: 599, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层抢救室烟感"},
-	{Field: "yg0258", Address: 600, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg0259", Address: 601, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg025a", Address: 602, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层楼梯口烟感"},
-	{Field: "yg025b", Address: 603, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg025c", Address: 604, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg025d", Address: 605, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg025e", Address: 606, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg025f", Address: 607, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg0260", Address: 608, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
-	{Field: "yg0261", Address: 609, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层楼梯烟感"},
-	{Field: "yg0262", Address: 610, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道烟感"},
-	{Field: "yg0263", Address: 611, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层烟感"},
-	{Field: "yg0264", Address: 612, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道烟感"},
-	{Field: "yg0265", Address: 613, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道烟感"},
-	{Field: "yg0266", Address: 614, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道烟感"},
-	{Field: "yg0267", Address: 615, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层护士站烟感"},
-	{Field: "sb0268", Address: 616, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道烟感"},
-	{Field: "sb0269", Address: 617, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道手报"},
-	{Field: "sb026a", Address: 618, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道手报"},
-	{Field: "sb026b", Address: 619, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道手报"},
-	{Field: "xb026c", Address: 620, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道消报"},
-	{Field: "xb026d", Address: 621, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道消报"},
-	{Field: "xb026e", Address: 622, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道消报"},
-	{Field: "sl026f", Address: 623, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道水流"},
-	{Field: "xhdf0270", Address: 624, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道信号蝶阀"},
-	{Field: "sg0271", Address: 625, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道声光"},
-	{Field: "sg0272", Address: 626, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道声光"},
-	{Field: "sg0273", Address: 627, Scale: 1, Decimals: 0, RW: "R", Unit: "", Label: "3层走道声光"},
-}
-
-var addrToIndexes map[uint16][]int
-
-func init() {
-	addrToIndexes = make(map[uint16][]int)
-	for i, p := range pointConfig {
-		addrToIndexes[p.Address] = append(addrToIndexes[p.Address], i)
-	}
+// 点表功能码为 0x03，实际读取时失败回退 0x04；单次请求不超过 50 个寄存器
+// 【自动生成】pointgen -max 50
+// 由 points.xlsx 生成，请勿手工修改；修改点表后执行 make points
+var pointConfig = []point.Point{
+	{Field: "yg0101", FuncCode: modbus.FuncReadHolding, Address: 257, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层心电烟感"},
+	{Field: "yg0102", FuncCode: modbus.FuncReadHolding, Address: 258, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层心电烟感"},
+	{Field: "yg0103", FuncCode: modbus.FuncReadHolding, Address: 259, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层外科烟感"},
+	{Field: "yg0104", FuncCode: modbus.FuncReadHolding, Address: 260, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层外科烟感"},
+	{Field: "yg0105", FuncCode: modbus.FuncReadHolding, Address: 261, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层外科烟感"},
+	{Field: "yg0106", FuncCode: modbus.FuncReadHolding, Address: 262, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层B超烟感"},
+	{Field: "yg0107", FuncCode: modbus.FuncReadHolding, Address: 263, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层楼梯口烟感"},
+	{Field: "yg0108", FuncCode: modbus.FuncReadHolding, Address: 264, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层楼梯口烟感"},
+	{Field: "yg0109", FuncCode: modbus.FuncReadHolding, Address: 265, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层化验科烟感"},
+	{Field: "yg010a", FuncCode: modbus.FuncReadHolding, Address: 266, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层化验科烟感"},
+	{Field: "yg010b", FuncCode: modbus.FuncReadHolding, Address: 267, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层化验科烟感"},
+	{Field: "yg010c", FuncCode: modbus.FuncReadHolding, Address: 268, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层化验科烟感"},
+	{Field: "yg010d", FuncCode: modbus.FuncReadHolding, Address: 269, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层化验科烟感"},
+	{Field: "yg010e", FuncCode: modbus.FuncReadHolding, Address: 270, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层化验科烟感"},
+	{Field: "yg010f", FuncCode: modbus.FuncReadHolding, Address: 271, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层化验科烟感"},
+	{Field: "yg0110", FuncCode: modbus.FuncReadHolding, Address: 272, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层全科诊室烟感"},
+	{Field: "yg0111", FuncCode: modbus.FuncReadHolding, Address: 273, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层全科诊室烟感"},
+	{Field: "yg0112", FuncCode: modbus.FuncReadHolding, Address: 274, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层全科诊室烟感"},
+	{Field: "yg0113", FuncCode: modbus.FuncReadHolding, Address: 275, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层全科诊室烟感"},
+	{Field: "yg0114", FuncCode: modbus.FuncReadHolding, Address: 276, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层全科诊室烟感"},
+	{Field: "yg0115", FuncCode: modbus.FuncReadHolding, Address: 277, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层全科诊室烟感"},
+	{Field: "yg0116", FuncCode: modbus.FuncReadHolding, Address: 278, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层全科诊室烟感"},
+	{Field: "yg0117", FuncCode: modbus.FuncReadHolding, Address: 279, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层大门口烟感"},
+	{Field: "yg0118", FuncCode: modbus.FuncReadHolding, Address: 280, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层更衣室烟感"},
+	{Field: "yg0119", FuncCode: modbus.FuncReadHolding, Address: 281, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层收费处烟感"},
+	{Field: "yg011a", FuncCode: modbus.FuncReadHolding, Address: 282, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层五官科烟感"},
+	{Field: "yg011b", FuncCode: modbus.FuncReadHolding, Address: 283, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层五官科烟感"},
+	{Field: "yg011c", FuncCode: modbus.FuncReadHolding, Address: 284, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层五官科烟感"},
+	{Field: "yg011d", FuncCode: modbus.FuncReadHolding, Address: 285, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层垃圾房烟感"},
+	{Field: "yg011e", FuncCode: modbus.FuncReadHolding, Address: 286, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层补液大厅烟感"},
+	{Field: "yg011f", FuncCode: modbus.FuncReadHolding, Address: 287, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层注射室烟感"},
+	{Field: "yg0120", FuncCode: modbus.FuncReadHolding, Address: 288, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层配电室烟感"},
+	{Field: "yg0121", FuncCode: modbus.FuncReadHolding, Address: 289, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层补液大厅烟感"},
+	{Field: "yg0122", FuncCode: modbus.FuncReadHolding, Address: 290, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层补液大厅烟感"},
+	{Field: "yg0123", FuncCode: modbus.FuncReadHolding, Address: 291, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层抢救室烟感"},
+	{Field: "yg0124", FuncCode: modbus.FuncReadHolding, Address: 292, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层配电室烟感"},
+	{Field: "yg0125", FuncCode: modbus.FuncReadHolding, Address: 293, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
+	{Field: "yg0126", FuncCode: modbus.FuncReadHolding, Address: 294, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
+	{Field: "yg0127", FuncCode: modbus.FuncReadHolding, Address: 295, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层药库烟感"},
+	{Field: "yg0128", FuncCode: modbus.FuncReadHolding, Address: 296, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层药库烟感"},
+	{Field: "yg0129", FuncCode: modbus.FuncReadHolding, Address: 297, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层药库烟感"},
+	{Field: "yg012a", FuncCode: modbus.FuncReadHolding, Address: 298, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
+	{Field: "yg012b", FuncCode: modbus.FuncReadHolding, Address: 299, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
+	{Field: "yg012c", FuncCode: modbus.FuncReadHolding, Address: 300, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
+	{Field: "yg012d", FuncCode: modbus.FuncReadHolding, Address: 301, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
+	{Field: "yg012e", FuncCode: modbus.FuncReadHolding, Address: 302, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
+	{Field: "yg012f", FuncCode: modbus.FuncReadHolding, Address: 303, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
+	{Field: "yg0130", FuncCode: modbus.FuncReadHolding, Address: 304, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
+	{Field: "yg0131", FuncCode: modbus.FuncReadHolding, Address: 305, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道烟感"},
+	{Field: "sb0132", FuncCode: modbus.FuncReadHolding, Address: 306, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道手报"},
+	{Field: "sb0133", FuncCode: modbus.FuncReadHolding, Address: 307, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道手报"},
+	{Field: "sb0134", FuncCode: modbus.FuncReadHolding, Address: 308, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道手报"},
+	{Field: "xb0135", FuncCode: modbus.FuncReadHolding, Address: 309, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道消报"},
+	{Field: "xb0136", FuncCode: modbus.FuncReadHolding, Address: 310, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道消报"},
+	{Field: "xb0137", FuncCode: modbus.FuncReadHolding, Address: 311, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层消报"},
+	{Field: "xb0138", FuncCode: modbus.FuncReadHolding, Address: 312, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层消报"},
+	{Field: "slzs0139", FuncCode: modbus.FuncReadHolding, Address: 313, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层水流指示"},
+	{Field: "xhf013a", FuncCode: modbus.FuncReadHolding, Address: 314, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层信号阀"},
+	{Field: "sg013b", FuncCode: modbus.FuncReadHolding, Address: 315, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层声光"},
+	{Field: "sg013c", FuncCode: modbus.FuncReadHolding, Address: 316, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层声光"},
+	{Field: "sg013d", FuncCode: modbus.FuncReadHolding, Address: 317, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层声光"},
+	{Field: "yg013e", FuncCode: modbus.FuncReadHolding, Address: 318, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中医诊室烟感"},
+	{Field: "yg013f", FuncCode: modbus.FuncReadHolding, Address: 319, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中医诊室烟感"},
+	{Field: "yg0140", FuncCode: modbus.FuncReadHolding, Address: 320, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中医诊室烟感"},
+	{Field: "yg0141", FuncCode: modbus.FuncReadHolding, Address: 321, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层楼梯口烟感"},
+	{Field: "yg0142", FuncCode: modbus.FuncReadHolding, Address: 322, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中医诊室烟感"},
+	{Field: "yg0143", FuncCode: modbus.FuncReadHolding, Address: 323, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中医诊室烟感"},
+	{Field: "yg0144", FuncCode: modbus.FuncReadHolding, Address: 324, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中医诊室烟感"},
+	{Field: "yg0145", FuncCode: modbus.FuncReadHolding, Address: 325, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中医诊室烟感"},
+	{Field: "yg0146", FuncCode: modbus.FuncReadHolding, Address: 326, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层口腔科烟感"},
+	{Field: "yg0147", FuncCode: modbus.FuncReadHolding, Address: 327, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层口腔科烟感"},
+	{Field: "yg0148", FuncCode: modbus.FuncReadHolding, Address: 328, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层口腔科烟感"},
+	{Field: "yg0149", FuncCode: modbus.FuncReadHolding, Address: 329, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层口腔科烟感"},
+	{Field: "yg014a", FuncCode: modbus.FuncReadHolding, Address: 330, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层机房烟感"},
+	{Field: "yg014b", FuncCode: modbus.FuncReadHolding, Address: 331, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层楼梯口烟感"},
+	{Field: "yg014c", FuncCode: modbus.FuncReadHolding, Address: 332, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层康复诊疗室烟感"},
+	{Field: "yg014d", FuncCode: modbus.FuncReadHolding, Address: 333, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层康复诊疗室烟感"},
+	{Field: "yg014e", FuncCode: modbus.FuncReadHolding, Address: 334, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层全科诊室烟感"},
+	{Field: "yg014f", FuncCode: modbus.FuncReadHolding, Address: 335, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层心电图室烟感"},
+	{Field: "yg0150", FuncCode: modbus.FuncReadHolding, Address: 336, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层医生办公室烟感"},
+	{Field: "yg0151", FuncCode: modbus.FuncReadHolding, Address: 337, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层医生办公室烟感"},
+	{Field: "yg0152", FuncCode: modbus.FuncReadHolding, Address: 338, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层医生办公室烟感"},
+	{Field: "yg0153", FuncCode: modbus.FuncReadHolding, Address: 339, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中药房烟感"},
+	{Field: "yg0154", FuncCode: modbus.FuncReadHolding, Address: 340, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层收费烟感"},
+	{Field: "yg0155", FuncCode: modbus.FuncReadHolding, Address: 341, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层楼梯口烟感"},
+	{Field: "yg0156", FuncCode: modbus.FuncReadHolding, Address: 342, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
+	{Field: "yg0157", FuncCode: modbus.FuncReadHolding, Address: 343, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
+	{Field: "yg0158", FuncCode: modbus.FuncReadHolding, Address: 344, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
+	{Field: "yg0159", FuncCode: modbus.FuncReadHolding, Address: 345, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
+	{Field: "wg015a", FuncCode: modbus.FuncReadHolding, Address: 346, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中医便诊室温感"},
+	{Field: "wg015b", FuncCode: modbus.FuncReadHolding, Address: 347, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中医便诊室温感"},
+	{Field: "wg015c", FuncCode: modbus.FuncReadHolding, Address: 348, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中医便诊室温感"},
+	{Field: "wg015d", FuncCode: modbus.FuncReadHolding, Address: 349, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中医便诊室温感"},
+	{Field: "wg015e", FuncCode: modbus.FuncReadHolding, Address: 350, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中医便诊室温感"},
+	{Field: "wg015f", FuncCode: modbus.FuncReadHolding, Address: 351, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中医便诊室温感"},
+	{Field: "wg0160", FuncCode: modbus.FuncReadHolding, Address: 352, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层中医便诊室温感"},
+	{Field: "sb0161", FuncCode: modbus.FuncReadHolding, Address: 353, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道手报"},
+	{Field: "sb0162", FuncCode: modbus.FuncReadHolding, Address: 354, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道手报"},
+	{Field: "sb0163", FuncCode: modbus.FuncReadHolding, Address: 355, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道手报"},
+	{Field: "xb0164", FuncCode: modbus.FuncReadHolding, Address: 356, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道消报"},
+	{Field: "xb0165", FuncCode: modbus.FuncReadHolding, Address: 357, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道消报"},
+	{Field: "xb0166", FuncCode: modbus.FuncReadHolding, Address: 358, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道消报"},
+	{Field: "xb0167", FuncCode: modbus.FuncReadHolding, Address: 359, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道消报"},
+	{Field: "xhf0168", FuncCode: modbus.FuncReadHolding, Address: 360, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层信号阀"},
+	{Field: "lszs0169", FuncCode: modbus.FuncReadHolding, Address: 361, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层水流指示"},
+	{Field: "sg016a", FuncCode: modbus.FuncReadHolding, Address: 362, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层声光"},
+	{Field: "sg016b", FuncCode: modbus.FuncReadHolding, Address: 363, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层声光"},
+	{Field: "sg016c", FuncCode: modbus.FuncReadHolding, Address: 364, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层声光"},
+	{Field: "yg016d", FuncCode: modbus.FuncReadHolding, Address: 365, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层杂物间烟感"},
+	{Field: "yg016e", FuncCode: modbus.FuncReadHolding, Address: 366, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层档案室烟感"},
+	{Field: "yg016f", FuncCode: modbus.FuncReadHolding, Address: 367, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层档案室烟感"},
+	{Field: "yg0170", FuncCode: modbus.FuncReadHolding, Address: 368, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层档案室烟感"},
+	{Field: "yg0171", FuncCode: modbus.FuncReadHolding, Address: 369, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层杂物间烟感"},
+	{Field: "yg0172", FuncCode: modbus.FuncReadHolding, Address: 370, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层餐厅烟感"},
+	{Field: "yg0173", FuncCode: modbus.FuncReadHolding, Address: 371, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层餐厅烟感"},
+	{Field: "yg0174", FuncCode: modbus.FuncReadHolding, Address: 372, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层餐厅烟感"},
+	{Field: "yg0175", FuncCode: modbus.FuncReadHolding, Address: 373, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层摄片机房烟感"},
+	{Field: "yg0176", FuncCode: modbus.FuncReadHolding, Address: 374, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层摄片机房烟感"},
+	{Field: "yg0177", FuncCode: modbus.FuncReadHolding, Address: 375, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层摄片机房烟感"},
+	{Field: "yg0178", FuncCode: modbus.FuncReadHolding, Address: 376, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层机房控制室烟感"},
+	{Field: "yg0179", FuncCode: modbus.FuncReadHolding, Address: 377, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层候诊室烟感"},
+	{Field: "yg017a", FuncCode: modbus.FuncReadHolding, Address: 378, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层登记室烟感"},
+	{Field: "yg017b", FuncCode: modbus.FuncReadHolding, Address: 379, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层机房控制室烟感"},
+	{Field: "yg017c", FuncCode: modbus.FuncReadHolding, Address: 380, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层透视机房烟感"},
+	{Field: "wg017d", FuncCode: modbus.FuncReadHolding, Address: 381, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层厨房温感"},
+	{Field: "wg017e", FuncCode: modbus.FuncReadHolding, Address: 382, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层厨房温感"},
+	{Field: "wg017f", FuncCode: modbus.FuncReadHolding, Address: 383, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层厨房温感"},
+	{Field: "xb0180", FuncCode: modbus.FuncReadHolding, Address: 384, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层候诊室消报"},
+	{Field: "xb0181", FuncCode: modbus.FuncReadHolding, Address: 385, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层候诊室消报"},
+	{Field: "xb0182", FuncCode: modbus.FuncReadHolding, Address: 386, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道消报"},
+	{Field: "xb0183", FuncCode: modbus.FuncReadHolding, Address: 387, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道消报"},
+	{Field: "xb0184", FuncCode: modbus.FuncReadHolding, Address: 388, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道消报"},
+	{Field: "xhf0185", FuncCode: modbus.FuncReadHolding, Address: 389, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道信号阀"},
+	{Field: "slzs0186", FuncCode: modbus.FuncReadHolding, Address: 390, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道水流指示"},
+	{Field: "sg0187", FuncCode: modbus.FuncReadHolding, Address: 391, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "1层走道声光"},
+	{Field: "yg0188", FuncCode: modbus.FuncReadHolding, Address: 392, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层办公室烟感"},
+	{Field: "yg0189", FuncCode: modbus.FuncReadHolding, Address: 393, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层办公室烟感"},
+	{Field: "yg018a", FuncCode: modbus.FuncReadHolding, Address: 394, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层测智室烟感"},
+	{Field: "yg018b", FuncCode: modbus.FuncReadHolding, Address: 395, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层测智室烟感"},
+	{Field: "yg018c", FuncCode: modbus.FuncReadHolding, Address: 396, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层儿科室烟感"},
+	{Field: "yg018d", FuncCode: modbus.FuncReadHolding, Address: 397, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层计划免疫烟感"},
+	{Field: "yg018e", FuncCode: modbus.FuncReadHolding, Address: 398, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层计划免疫烟感"},
+	{Field: "yg018f", FuncCode: modbus.FuncReadHolding, Address: 399, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层计划免疫烟感"},
+	{Field: "yg0190", FuncCode: modbus.FuncReadHolding, Address: 400, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层冷库烟感"},
+	{Field: "yg0191", FuncCode: modbus.FuncReadHolding, Address: 401, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层215房间烟感"},
+	{Field: "yg0192", FuncCode: modbus.FuncReadHolding, Address: 402, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层216房间烟感"},
+	{Field: "yg0193", FuncCode: modbus.FuncReadHolding, Address: 403, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层217房间烟感"},
+	{Field: "yg0194", FuncCode: modbus.FuncReadHolding, Address: 404, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层母婴室烟感"},
+	{Field: "yg0195", FuncCode: modbus.FuncReadHolding, Address: 405, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
+	{Field: "yg0196", FuncCode: modbus.FuncReadHolding, Address: 406, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
+	{Field: "yg0197", FuncCode: modbus.FuncReadHolding, Address: 407, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
+	{Field: "yg0198", FuncCode: modbus.FuncReadHolding, Address: 408, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道烟感"},
+	{Field: "sb0199", FuncCode: modbus.FuncReadHolding, Address: 409, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道手报"},
+	{Field: "sb019a", FuncCode: modbus.FuncReadHolding, Address: 410, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道手报"},
+	{Field: "xb019b", FuncCode: modbus.FuncReadHolding, Address: 411, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道消报"},
+	{Field: "xb019c", FuncCode: modbus.FuncReadHolding, Address: 412, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道消报"},
+	{Field: "xhdf019d", FuncCode: modbus.FuncReadHolding, Address: 413, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道信号蝶阀"},
+	{Field: "slzs019e", FuncCode: modbus.FuncReadHolding, Address: 414, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道水流指示"},
+	{Field: "sg019f", FuncCode: modbus.FuncReadHolding, Address: 415, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道声光"},
+	{Field: "sg01a0", FuncCode: modbus.FuncReadHolding, Address: 416, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "2层走道声光"},
+	{Field: "yg0201", FuncCode: modbus.FuncReadHolding, Address: 513, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层院长办公室烟感"},
+	{Field: "yg0202", FuncCode: modbus.FuncReadHolding, Address: 514, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层副院长办公室烟感"},
+	{Field: "yg0203", FuncCode: modbus.FuncReadHolding, Address: 515, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层示范性教育室烟感"},
+	{Field: "yg0204", FuncCode: modbus.FuncReadHolding, Address: 516, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层档案室烟感"},
+	{Field: "yg0205", FuncCode: modbus.FuncReadHolding, Address: 517, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层人事部烟感"},
+	{Field: "yg0206", FuncCode: modbus.FuncReadHolding, Address: 518, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层党支部烟感"},
+	{Field: "yg0207", FuncCode: modbus.FuncReadHolding, Address: 519, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层办公室烟感"},
+	{Field: "yg0208", FuncCode: modbus.FuncReadHolding, Address: 520, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层医疗办公室烟感"},
+	{Field: "yg0209", FuncCode: modbus.FuncReadHolding, Address: 521, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层财务室烟感"},
+	{Field: "yg020a", FuncCode: modbus.FuncReadHolding, Address: 522, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层楼梯口烟感"},
+	{Field: "yg020b", FuncCode: modbus.FuncReadHolding, Address: 523, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层电梯机房烟感"},
+	{Field: "yg020c", FuncCode: modbus.FuncReadHolding, Address: 524, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层仓库烟感"},
+	{Field: "yg020d", FuncCode: modbus.FuncReadHolding, Address: 525, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层会议室烟感"},
+	{Field: "yg020e", FuncCode: modbus.FuncReadHolding, Address: 526, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层会议室烟感"},
+	{Field: "yg020f", FuncCode: modbus.FuncReadHolding, Address: 527, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层楼梯口烟感"},
+	{Field: "yg0210", FuncCode: modbus.FuncReadHolding, Address: 528, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层医生值班室烟感"},
+	{Field: "yg0211", FuncCode: modbus.FuncReadHolding, Address: 529, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层走道烟感"},
+	{Field: "yg0212", FuncCode: modbus.FuncReadHolding, Address: 530, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层走道烟感"},
+	{Field: "yg0213", FuncCode: modbus.FuncReadHolding, Address: 531, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层走道烟感"},
+	{Field: "yg0214", FuncCode: modbus.FuncReadHolding, Address: 532, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层走道烟感"},
+	{Field: "sb0215", FuncCode: modbus.FuncReadHolding, Address: 533, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层走道手报"},
+	{Field: "sg0216", FuncCode: modbus.FuncReadHolding, Address: 534, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层走道声光"},
+	{Field: "sb0217", FuncCode: modbus.FuncReadHolding, Address: 535, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层走道手报"},
+	{Field: "sg0218", FuncCode: modbus.FuncReadHolding, Address: 536, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层走道声光"},
+	{Field: "xb0219", FuncCode: modbus.FuncReadHolding, Address: 537, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层走道消报"},
+	{Field: "xb021a", FuncCode: modbus.FuncReadHolding, Address: 538, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层走道消报"},
+	{Field: "sl021b", FuncCode: modbus.FuncReadHolding, Address: 539, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层走道水流"},
+	{Field: "xhf021c", FuncCode: modbus.FuncReadHolding, Address: 540, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "5层走道信号阀"},
+	{Field: "yg021d", FuncCode: modbus.FuncReadHolding, Address: 541, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层储物间烟感"},
+	{Field: "yg021e", FuncCode: modbus.FuncReadHolding, Address: 542, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
+	{Field: "yg021f", FuncCode: modbus.FuncReadHolding, Address: 543, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
+	{Field: "yg0220", FuncCode: modbus.FuncReadHolding, Address: 544, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层楼梯口烟感"},
+	{Field: "yg0221", FuncCode: modbus.FuncReadHolding, Address: 545, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层vct室烟感"},
+	{Field: "yg0222", FuncCode: modbus.FuncReadHolding, Address: 546, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层信息管理烟感"},
+	{Field: "yg0223", FuncCode: modbus.FuncReadHolding, Address: 547, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层楼梯口烟感"},
+	{Field: "yg0224", FuncCode: modbus.FuncReadHolding, Address: 548, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
+	{Field: "yg0225", FuncCode: modbus.FuncReadHolding, Address: 549, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
+	{Field: "yg0226", FuncCode: modbus.FuncReadHolding, Address: 550, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
+	{Field: "yg0227", FuncCode: modbus.FuncReadHolding, Address: 551, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
+	{Field: "yg0228", FuncCode: modbus.FuncReadHolding, Address: 552, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
+	{Field: "yg0229", FuncCode: modbus.FuncReadHolding, Address: 553, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
+	{Field: "yg022a", FuncCode: modbus.FuncReadHolding, Address: 554, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
+	{Field: "yg022b", FuncCode: modbus.FuncReadHolding, Address: 555, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
+	{Field: "yg022c", FuncCode: modbus.FuncReadHolding, Address: 556, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
+	{Field: "yg022d", FuncCode: modbus.FuncReadHolding, Address: 557, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
+	{Field: "yg022e", FuncCode: modbus.FuncReadHolding, Address: 558, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
+	{Field: "yg022f", FuncCode: modbus.FuncReadHolding, Address: 559, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
+	{Field: "yg0230", FuncCode: modbus.FuncReadHolding, Address: 560, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
+	{Field: "yg0231", FuncCode: modbus.FuncReadHolding, Address: 561, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层口腔烟感"},
+	{Field: "yg0232", FuncCode: modbus.FuncReadHolding, Address: 562, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层杂物间烟感"},
+	{Field: "yg0233", FuncCode: modbus.FuncReadHolding, Address: 563, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
+	{Field: "yg0234", FuncCode: modbus.FuncReadHolding, Address: 564, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
+	{Field: "yg0235", FuncCode: modbus.FuncReadHolding, Address: 565, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
+	{Field: "yg0236", FuncCode: modbus.FuncReadHolding, Address: 566, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
+	{Field: "yg0237", FuncCode: modbus.FuncReadHolding, Address: 567, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
+	{Field: "yg0238", FuncCode: modbus.FuncReadHolding, Address: 568, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层诊室烟感"},
+	{Field: "yg0239", FuncCode: modbus.FuncReadHolding, Address: 569, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层走道烟感"},
+	{Field: "yg023a", FuncCode: modbus.FuncReadHolding, Address: 570, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层走道烟感"},
+	{Field: "yg023b", FuncCode: modbus.FuncReadHolding, Address: 571, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层走道烟感"},
+	{Field: "yg023c", FuncCode: modbus.FuncReadHolding, Address: 572, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层走道烟感"},
+	{Field: "sb023d", FuncCode: modbus.FuncReadHolding, Address: 573, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层走道手报"},
+	{Field: "sb023e", FuncCode: modbus.FuncReadHolding, Address: 574, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层走道手报"},
+	{Field: "xb023f", FuncCode: modbus.FuncReadHolding, Address: 575, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层走道消报"},
+	{Field: "xb0240", FuncCode: modbus.FuncReadHolding, Address: 576, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层走道消报"},
+	{Field: "xb0241", FuncCode: modbus.FuncReadHolding, Address: 577, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层走道消报"},
+	{Field: "sl0242", FuncCode: modbus.FuncReadHolding, Address: 578, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层走道水流"},
+	{Field: "sl0243", FuncCode: modbus.FuncReadHolding, Address: 579, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层走道水流"},
+	{Field: "sg0244", FuncCode: modbus.FuncReadHolding, Address: 580, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层走道声光"},
+	{Field: "sg0245", FuncCode: modbus.FuncReadHolding, Address: 581, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "4层走道声光"},
+	{Field: "yg0246", FuncCode: modbus.FuncReadHolding, Address: 582, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg0247", FuncCode: modbus.FuncReadHolding, Address: 583, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg0248", FuncCode: modbus.FuncReadHolding, Address: 584, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg0249", FuncCode: modbus.FuncReadHolding, Address: 585, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg024a", FuncCode: modbus.FuncReadHolding, Address: 586, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg024b", FuncCode: modbus.FuncReadHolding, Address: 587, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg024c", FuncCode: modbus.FuncReadHolding, Address: 588, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg024d", FuncCode: modbus.FuncReadHolding, Address: 589, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg024e", FuncCode: modbus.FuncReadHolding, Address: 590, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层治疗准备室烟感"},
+	{Field: "yg024f", FuncCode: modbus.FuncReadHolding, Address: 591, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层护士休息室烟感"},
+	{Field: "yg0250", FuncCode: modbus.FuncReadHolding, Address: 592, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层护士办公室烟感"},
+	{Field: "yg0251", FuncCode: modbus.FuncReadHolding, Address: 593, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层康复教室烟感"},
+	{Field: "yg0252", FuncCode: modbus.FuncReadHolding, Address: 594, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道烟感"},
+	{Field: "yg0253", FuncCode: modbus.FuncReadHolding, Address: 595, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层医生值班室烟感"},
+	{Field: "yg0254", FuncCode: modbus.FuncReadHolding, Address: 596, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层卫生间烟感"},
+	{Field: "yg0255", FuncCode: modbus.FuncReadHolding, Address: 597, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层医生办公室烟感"},
+	{Field: "yg0256", FuncCode: modbus.FuncReadHolding, Address: 598, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层医生办公室烟感"},
+	{Field: "yg0257", FuncCode: modbus.FuncReadHolding, Address: 599, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层抢救室烟感"},
+	{Field: "yg0258", FuncCode: modbus.FuncReadHolding, Address: 600, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg0259", FuncCode: modbus.FuncReadHolding, Address: 601, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg025a", FuncCode: modbus.FuncReadHolding, Address: 602, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层楼梯口烟感"},
+	{Field: "yg025b", FuncCode: modbus.FuncReadHolding, Address: 603, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg025c", FuncCode: modbus.FuncReadHolding, Address: 604, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg025d", FuncCode: modbus.FuncReadHolding, Address: 605, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg025e", FuncCode: modbus.FuncReadHolding, Address: 606, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg025f", FuncCode: modbus.FuncReadHolding, Address: 607, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg0260", FuncCode: modbus.FuncReadHolding, Address: 608, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层病房烟感"},
+	{Field: "yg0261", FuncCode: modbus.FuncReadHolding, Address: 609, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层楼梯烟感"},
+	{Field: "yg0262", FuncCode: modbus.FuncReadHolding, Address: 610, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道烟感"},
+	{Field: "yg0263", FuncCode: modbus.FuncReadHolding, Address: 611, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层烟感"},
+	{Field: "yg0264", FuncCode: modbus.FuncReadHolding, Address: 612, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道烟感"},
+	{Field: "yg0265", FuncCode: modbus.FuncReadHolding, Address: 613, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道烟感"},
+	{Field: "yg0266", FuncCode: modbus.FuncReadHolding, Address: 614, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道烟感"},
+	{Field: "yg0267", FuncCode: modbus.FuncReadHolding, Address: 615, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层护士站烟感"},
+	{Field: "sb0268", FuncCode: modbus.FuncReadHolding, Address: 616, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道烟感"},
+	{Field: "sb0269", FuncCode: modbus.FuncReadHolding, Address: 617, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道手报"},
+	{Field: "sb026a", FuncCode: modbus.FuncReadHolding, Address: 618, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道手报"},
+	{Field: "sb026b", FuncCode: modbus.FuncReadHolding, Address: 619, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道手报"},
+	{Field: "xb026c", FuncCode: modbus.FuncReadHolding, Address: 620, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道消报"},
+	{Field: "xb026d", FuncCode: modbus.FuncReadHolding, Address: 621, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道消报"},
+	{Field: "xb026e", FuncCode: modbus.FuncReadHolding, Address: 622, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道消报"},
+	{Field: "sl026f", FuncCode: modbus.FuncReadHolding, Address: 623, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道水流"},
+	{Field: "xhdf0270", FuncCode: modbus.FuncReadHolding, Address: 624, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道信号蝶阀"},
+	{Field: "sg0271", FuncCode: modbus.FuncReadHolding, Address: 625, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道声光"},
+	{Field: "sg0272", FuncCode: modbus.FuncReadHolding, Address: 626, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道声光"},
+	{Field: "sg0273", FuncCode: modbus.FuncReadHolding, Address: 627, Length: 1, DataType: point.TypeUint16, Scale: 1, Expr: "v", Decimals: 0, RW: "R", Unit: "", Label: "3层走道声光"},
 }
 
+// 读取分组
+var readBlocks = []modbus.Block{
+	{FuncCode: modbus.FuncReadHolding, Start: 257, Count: 50},
+	{FuncCode: modbus.FuncReadHolding, Start: 307, Count: 50},
+	{FuncCode: modbus.FuncReadHolding, Start: 357, Count: 50},
+	{FuncCode: modbus.FuncReadHolding, Start: 407, Count: 10},
+	{FuncCode: modbus.FuncReadHolding, Start: 513, Count: 50},
+	{FuncCode: modbus.FuncReadHolding, Start: 563, Count: 50},
+	{FuncCode: modbus.FuncReadHolding, Start: 613, Count: 15},
+}
+
+// 【自动生成】结束
+
 //go:wasmexport handle
 func handle() int32 {
-	defer func() {
-		if r := recover(); r != nil {
-			outputJSON(map[string]interface{}{"success": false, "error": "panic"})
-		}
-	}()
+	defer driver.Recover()
 
-	cfg := getConfig()
-	points := readAllPoints(cfg.DeviceAddress, cfg.Debug)
+	cfg := driver.GetConfig()
+	client := driver.NewRTUClient(serial_transceive, cfg.Debug)
 
-	outputJSON(map[string]interface{}{
-		"success": true,
-		"points":  points,
-	})
+	if name := strings.ToLower(cfg.FuncName); name != "read" {
+		driver.OutputJSON(runCommand(client, cfg, name))
+		return 0
+	}
+
+	table, err := panelPoints(cfg)
+	if err != nil {
+		driver.OutputJSON(map[string]interface{}{"success": false, "error": err.Error()})
+		return 0
+	}
+	codes, err := fire.ParseCodes(cfg.Raw["alarm_codes"], cfg.Raw["fault_codes"])
+	if err != nil {
+		driver.OutputJSON(map[string]interface{}{"success": false, "error": "config." + err.Error()})
+		return 0
+	}
+	points, errs := readAllPoints(client, cfg, table)
+
+	result := point.Result(points, errs)
+	events, sum := trackStates(cfg.DeviceAddress, table, points, codes)
+	result["events"] = events
+	result["alarms"] = sum.Alarms
+	result["faults"] = sum.Faults
+	result["stale"] = sum.Stale
+	result["summary"] = sum
+	driver.OutputJSON(result)
 	return 0
 }
 
 //go:wasmexport describe
 func describe() int32 {
-	outputJSON(map[string]interface{}{
+	// config.states 非法时仍描述点表本身
+	table, err := panelPoints(driver.GetConfig())
+	if err != nil {
+		table = pointConfig
+	}
+	driver.OutputJSON(map[string]interface{}{
 		"success": true,
-		"data":    map[string]string{},
+		"data":    point.Describe(DriverVersion, "modbus-rtu", table, configKeys...),
+		"tags":    describeTags(),
 	})
 	return 0
 }
 
 //go:wasmexport version
 func version() int32 {
-	outputJSON(map[string]interface{}{
-		"success": true,
-		"data": map[string]string{
-			"version": DriverVersion,
-		},
-	})
+	driver.OutputVersion(DriverVersion)
 	return 0
 }
 
-func readAllPoints(devAddr int, debug bool) []map[string]interface{} {
-	points := make([]map[string]interface{}, 0, len(pointConfig))
-	valueByAddr := make(map[uint16]uint16, len(pointConfig))
+// 读取经验
+//
+// 消防主机部分寄存器不可读，且寻址方式（1 基 / 0 基）与功能码（03 / 04）因
+// 主机固件而异。首次读取时逐段二分探测，把可用的寻址方式、功能码与不可读
+// 的寄存器段保存在 Extism var 中；之后的轮询直接按保存的方式读取并跳过
+// 不可读段。每 revalidate_polls 次轮询或读取失败时重新探测。
+
+const planVar = "read_plan"
+
+// errKnownBad 已知不可读、本次轮询跳过的寄存器
+const errKnownBad = modbus.Error("register unreadable, skipped until revalidation")
+
+// 缺省每 100 次轮询完整重新探测一次
+const defaultRevalidatePolls = 100
+
+var configKeys = []point.ConfigKey{
+	{Key: "revalidate_polls", Type: "int", Default: "100", Desc: "每隔多少次轮询重新探测寻址方式与不可读寄存器"},
+	{Key: "states", Type: "string", Desc: "面板状态码含义（现场核对后配置），如 0=正常;1=火警;2=故障;3=屏蔽，用于点表未配置 配置:states 的测点"},
+	{Key: "alarm_codes", Type: "string", Desc: "计入 alarms 的取值，如 1 或 1,4；未配置时取状态含义为 火警 的取值"},
+	{Key: "fault_codes", Type: "string", Desc: "计入 faults 的取值，如 2；未配置时取状态含义为 故障 的取值"},
+	{Key: "control", Type: "json", Desc: "控制命令映射 {\"reset\":{\"func_code\":5,\"address\":1},…}，见 README"},
+	{Key: "confirm", Type: "string", Desc: "控制命令确认口令：reset / silence，或 isolate:<field_name> / enable:<field_name>"},
+}
 
-	ranges := []struct {
-		Start uint16
-		Count uint16
-	}{
-		{Start: 257, Count: 50},
-		{Start: 307, Count: 50},
-		{Start: 357, Count: 50},
-		{Start: 407, Count: 10},
-		{Start: 513, Count: 50},
-		{Start: 563, Count: 50},
-		{Start: 613, Count: 15},
-	}
+// readPlan 跨轮询保存的读取经验
+type readPlan struct {
+	FuncCode  byte   `json:"func_code"`  // 可用功能码，0 表示尚未探测
+	ZeroBased bool   `json:"zero_based"` // 请求地址 = 点表地址 - 1
+	Bad       []span `json:"bad"`        // 不可读寄存器段（点表地址），按地址排序
+	Polls     int    `json:"polls"`      // 距上次完整探测的轮询次数
+}
 
-	for _, rg := range ranges {
-		readRangeAdaptive(byte(devAddr), rg.Start, rg.Count, debug, valueByAddr)
-	}
+// span 连续寄存器段
+type span struct {
+	Start uint16 `json:"start"`
+	Count uint16 `json:"count"`
+}
 
-	if debug && len(valueByAddr) == 0 {
-		logf("no points collected after adaptive reads")
-	}
+func (s span) end() uint32 {
+	return uint32(s.Start) + uint32(s.Count)
+}
 
-	for _, cfg := range pointConfig {
-		raw, ok := valueByAddr[cfg.Address]
-		if !ok {
+// readable 去掉 [start, start+count) 中已知不可读的段
+func (p *readPlan) readable(start, count uint16) []span {
+	var out []span
+	cur, end := uint32(start), uint32(start)+uint32(count)
+	for _, b := range p.Bad {
+		if b.end() <= cur || uint32(b.Start) >= end {
 			continue
 		}
-		realVal := float64(raw) * cfg.Scale
-		points = append(points, map[string]interface{}{
-			"field_name": cfg.Field,
-			"value":      formatFloat(realVal, cfg.Decimals),
-			"rw":         cfg.RW,
-			"unit":       cfg.Unit,
-			"label":      cfg.Label,
-		})
+		if uint32(b.Start) > cur {
+			out = append(out, span{Start: uint16(cur), Count: uint16(uint32(b.Start) - cur)})
+		}
+		cur = b.end()
+	}
+	if cur < end {
+		out = append(out, span{Start: uint16(cur), Count: uint16(end - cur)})
 	}
-	return points
+	return out
 }
 
-func readRangeAdaptive(devAddr byte, logicalStart uint16, count uint16, debug bool, out map[uint16]uint16) {
-	if count == 0 {
-		return
+// unreadable 返回 [start, start+count) 中已知不可读的段
+func (p *readPlan) unreadable(start, count uint16) []span {
+	var out []span
+	end := uint32(start) + uint32(count)
+	for _, b := range p.Bad {
+		lo, hi := uint32(b.Start), b.end()
+		if lo < uint32(start) {
+			lo = uint32(start)
+		}
+		if hi > end {
+			hi = end
+		}
+		if lo < hi {
+			out = append(out, span{Start: uint16(lo), Count: uint16(hi - lo)})
+		}
 	}
+	return out
+}
 
-	values := readMultipleRegsLogical(devAddr, logicalStart, count, debug)
-	if values != nil {
-		for i, v := range values {
-			out[logicalStart+uint16(i)] = v
-		}
+// markBad 记录不可读寄存器，与相邻段合并
+func (p *readPlan) markBad(addr uint16) {
+	i := 0
+	for i < len(p.Bad) && p.Bad[i].end() < uint32(addr) {
+		i++
+	}
+	switch {
+	case i < len(p.Bad) && uint32(p.Bad[i].Start) <= uint32(addr) && uint32(addr) < p.Bad[i].end():
 		return
+	case i < len(p.Bad) && p.Bad[i].end() == uint32(addr):
+		p.Bad[i].Count++
+		if i+1 < len(p.Bad) && p.Bad[i+1].Start == addr+1 {
+			p.Bad[i].Count += p.Bad[i+1].Count
+			p.Bad = append(p.Bad[:i+1], p.Bad[i+2:]...)
+		}
+	case i < len(p.Bad) && p.Bad[i].Start == addr+1:
+		p.Bad[i].Start--
+		p.Bad[i].Count++
+	default:
+		p.Bad = append(p.Bad, span{})
+		copy(p.Bad[i+1:], p.Bad[i:])
+		p.Bad[i] = span{Start: addr, Count: 1}
 	}
+}
 
-	if count == 1 {
-		if debug {
-			logf("skip unreadable register=%d", logicalStart)
+// forget 清除 [start, start+count) 内的不可读记录（重新探测该段前调用）
+func (p *readPlan) forget(start, count uint16) {
+	var kept []span
+	for _, b := range p.Bad {
+		if b.end() <= uint32(start) || uint32(b.Start) >= uint32(start)+uint32(count) {
+			kept = append(kept, b)
+			continue
+		}
+		if b.Start < start {
+			kept = append(kept, span{Start: b.Start, Count: start - b.Start})
+		}
+		if end := uint32(start) + uint32(count); b.end() > end {
+			kept = append(kept, span{Start: uint16(end), Count: uint16(b.end() - end)})
 		}
-		return
 	}
-
-	half := count / 2
-	readRangeAdaptive(devAddr, logicalStart, half, debug, out)
-	readRangeAdaptive(devAddr, logicalStart+half, count-half, debug, out)
+	p.Bad = kept
 }
 
-func readMultipleRegsLogical(devAddr byte, logicalStart uint16, count uint16, debug bool) []uint16 {
-	if values := readMultipleRegs(devAddr, logicalStart, count, debug); values != nil {
-		return values
-	}
+// readMode 一种请求方式：功能码 + 寻址方式
+type readMode struct {
+	funcCode  byte
+	zeroBased bool
+}
 
-	if logicalStart == 0 {
-		return nil
-	}
+// 探测顺序：点表功能码优先，1 基优先
+var readModes = []readMode{
+	{FUNC_CODE_READ_HOLDING, false},
+	{FUNC_CODE_READ_INPUT, false},
+	{FUNC_CODE_READ_HOLDING, true},
+	{FUNC_CODE_READ_INPUT, true},
+}
 
-	if debug {
-		logf("retry with 0-based address logical=%d query=%d count=%d", logicalStart, logicalStart-1, count)
-	}
+// reader 一次轮询的读取上下文
+type reader struct {
+	client  *modbus.Client
+	devAddr byte
+	debug   bool
+	plan    readPlan
+	regs    point.Registers // 按点表地址与功能码 0x03 保存，与 pointConfig 对应
+	seen    map[uint16]bool // 本次轮询已取得值或已记录失败的寄存器（点表地址）
+
+	requests  int  // 本次轮询已发出的请求数
+	fixed     bool // 本次轮询已有成功读取，请求方式不再变化
+	responded bool // 本次轮询从站是否有过任何应答
+	timeouts  int  // 从站无应答时累计的超时次数
+}
 
-	return readMultipleRegs(devAddr, logicalStart-1, count, debug)
+// offline 一轮探测全部超时且从未应答，视为通信中断，放弃本次轮询
+func (r *reader) offline() bool {
+	return !r.responded && r.timeouts >= len(readModes)
 }
 
-func readMultipleRegs(devAddr byte, startReg uint16, count uint16, debug bool) []uint16 {
-	if count > 50 {
-		count = 50
+func readAllPoints(client *modbus.Client, cfg driver.Config, table []point.Point) ([]map[string]interface{}, []point.BlockError) {
+	key := driver.StateKey(planVar, cfg.DeviceAddress)
+	r := &reader{
+		client:  client,
+		devAddr: byte(cfg.DeviceAddress),
+		debug:   cfg.Debug,
 	}
+	driver.LoadState(key, &r.plan)
 
-	if values, err := readMultipleRegsWithFunc(devAddr, startReg, count, FUNC_CODE_READ_HOLDING, debug); err == nil {
-		return values
-	} else if debug {
-		logf("read holding failed addr=%d count=%d err=%v", startReg, count, err)
+	full := r.plan.FuncCode == 0 || r.plan.Polls >= revalidatePolls(cfg)
+	if full {
+		if cfg.Debug {
+			driver.Logf("revalidate read plan after %d polls", r.plan.Polls)
+		}
+		r.plan.Bad = nil
 	}
 
-	if values, err := readMultipleRegsWithFunc(devAddr, startReg, count, FUNC_CODE_READ_INPUT, debug); err == nil {
-		return values
-	} else if debug {
-		logf("read input failed addr=%d count=%d err=%v", startReg, count, err)
+	failed := false
+	for _, blk := range readBlocks {
+		if r.offline() {
+			r.failRest(blk.Start, blk.Count, 0)
+			continue
+		}
+		before := r.requests
+		if full {
+			r.probe(blk.Start, blk.Count)
+		} else {
+			failed = r.readKnown(blk.Start, blk.Count) || failed
+		}
+		if r.offline() {
+			r.failRest(blk.Start, blk.Count, r.requests-before)
+		}
 	}
 
-	return nil
+	switch {
+	case r.offline(), r.requests > 0 && !r.fixed:
+		// 通信中断或本次无一成功读取时不更新经验，避免把全部寄存器记为不可读
+		// 而在之后的轮询中跳过；首次探测失败时下次轮询重新探测
+		if cfg.Debug {
+			driver.Logf("no successful read, keep read plan")
+		}
+	case full || failed:
+		r.plan.Polls = 0
+		driver.SaveState(key, r.plan)
+	default:
+		r.plan.Polls++
+		driver.SaveState(key, r.plan)
+	}
+
+	return r.regs.Collect(table), r.regs.Errors()
 }
 
-func readMultipleRegsWithFunc(devAddr byte, startReg uint16, count uint16, funcCode byte, debug bool) ([]uint16, error) {
-	req := buildReadFrame(devAddr, startReg, count, funcCode)
-	if debug {
-		logf("rtu req fc=%02X % X", funcCode, req)
+// readKnown 按保存的方式读取一段，跳过已知不可读的寄存器；
+// 读取失败时重新探测失败的部分并返回 true
+func (r *reader) readKnown(start, count uint16) bool {
+	for _, sp := range r.plan.unreadable(start, count) {
+		r.fail(sp.Start, sp.Count, 0, errKnownBad)
 	}
+	failed := false
+	for _, sp := range r.plan.readable(start, count) {
+		if r.offline() {
+			break
+		}
+		mode := readMode{r.plan.FuncCode, r.plan.ZeroBased}
+		if values, err := r.read(mode, sp.Start, sp.Count); err == nil {
+			r.store(sp.Start, values)
+			continue
+		}
+		failed = true
+		r.plan.forget(sp.Start, sp.Count)
+		r.probe(sp.Start, sp.Count)
+	}
+	return failed
+}
 
-	resp, n := serialTransceive(req, int(count)*2+5, 1000)
-	if debug {
-		logf("rtu fc=%02X n=%d resp=%s", funcCode, n, hexPreview(resp, n, 24))
+// probe 逐段二分探测 [start, start+count)，记录可用的请求方式与不可读寄存器
+func (r *reader) probe(start, count uint16) {
+	if count == 0 || r.offline() {
+		return
 	}
-	if n <= 0 {
-		return nil, errf("read timeout")
+	values, attempts, err := r.readAnyMode(start, count)
+	if err == nil {
+		r.store(start, values)
+		return
 	}
-
-	values, err := parseReadResponse(resp[:n], devAddr, funcCode)
-	if err != nil {
-		return nil, err
+	if r.offline() {
+		return
 	}
-	if len(values) < int(count) {
-		return nil, errf("insufficient register data")
+	if count == 1 {
+		if r.debug {
+			driver.Logf("skip unreadable register=%d", start)
+		}
+		r.plan.markBad(start)
+		r.fail(start, 1, attempts, err)
+		return
 	}
-
-	return values, nil
+	half := count / 2
+	r.probe(start, half)
+	r.probe(start+half, count-half)
 }
 
-func serialTransceive(req []byte, respLen int, timeoutMs int) ([]byte, int) {
-	if len(req) == 0 || respLen <= 0 {
-		return nil, 0
-	}
-	reqMem := pdk.AllocateBytes(req)
-	defer reqMem.Free()
-	respMem := pdk.Allocate(respLen)
-	defer respMem.Free()
-
-	n := int(serial_transceive(
-		reqMem.Offset(), uint64(len(req)),
-		respMem.Offset(), uint64(respLen),
-		uint64(timeoutMs),
-	))
-	if n <= 0 {
-		return nil, n
+// readAnyMode 本次轮询尚无成功读取时依次尝试各请求方式（上次可用的方式
+// 优先），之后只用已确定的方式，避免单个寄存器因错位读取“成功”而改变方式
+func (r *reader) readAnyMode(start, count uint16) (values []uint16, attempts int, err error) {
+	modes := readModes
+	if r.plan.FuncCode != 0 {
+		known := readMode{r.plan.FuncCode, r.plan.ZeroBased}
+		if r.fixed {
+			modes = nil
+		}
+		modes = append([]readMode{known}, modes...)
 	}
-	if n > respLen {
-		n = respLen
+	err = modbus.ErrTimeout
+	tried := map[readMode]bool{}
+	for _, m := range modes {
+		if tried[m] || r.offline() {
+			continue
+		}
+		tried[m] = true
+		attempts++
+		if values, err = r.read(m, start, count); err == nil {
+			return values, attempts, nil
+		}
 	}
-
-	resp := make([]byte, n)
-	mem := pdk.NewMemory(respMem.Offset(), uint64(n))
-	mem.Load(resp)
-	return resp, n
+	return nil, attempts, err
 }
 
-func buildReadFrame(addr byte, start uint16, qty uint16, funcCode byte) []byte {
-	req := make([]byte, 8)
-	req[0] = addr
-	req[1] = funcCode
-	req[2], req[3] = byte(start>>8), byte(start)
-	req[4], req[5] = byte(qty>>8), byte(qty)
-	crc := crc16(req[:6])
-	req[6], req[7] = byte(crc), byte(crc>>8)
-	return req
+// read 按指定方式读取，start 为点表地址
+func (r *reader) read(m readMode, start, count uint16) ([]uint16, error) {
+	query := start
+	if m.zeroBased {
+		if start == 0 {
+			return nil, modbus.ErrInvalidResponse
+		}
+		query = start - 1
+	}
+	r.requests++
+	values, err := r.client.ReadRegisters(r.devAddr, m.funcCode, query, count)
+	switch {
+	case err == nil:
+		r.responded, r.fixed = true, true
+		r.plan.FuncCode, r.plan.ZeroBased = m.funcCode, m.zeroBased
+	case err == modbus.ErrTimeout:
+		r.timeouts++
+	default:
+		r.responded = true
+	}
+	if err != nil && r.debug {
+		driver.Logf("read fc=%d zero_based=%v addr=%d count=%d err=%v", m.funcCode, m.zeroBased, start, count, err)
+	}
+	return values, err
 }
 
-func parseReadResponse(data []byte, addr byte, funcCode byte) ([]uint16, error) {
-	if len(data) < 5 || data[0] != addr {
-		return nil, errf("invalid response")
-	}
-	if data[1] == (funcCode | 0x80) {
-		return nil, errf("modbus exception code=" + strconv.Itoa(int(data[2])))
-	}
-	if data[1] != funcCode {
-		return nil, errf("unexpected function code")
-	}
-	byteCnt := int(data[2])
-	if byteCnt < 2 || len(data) < 3+byteCnt+2 {
-		return nil, errf("byte count mismatch")
-	}
-	if !checkCRC(data[:3+byteCnt+2]) {
-		return nil, errf("crc error")
-	}
+func (r *reader) store(start uint16, values []uint16) {
+	r.regs.Put(FUNC_CODE_READ_HOLDING, start, values)
+	r.mark(start, uint16(len(values)))
+}
 
-	values := make([]uint16, byteCnt/2)
-	for i := 0; i < len(values); i++ {
-		values[i] = uint16(data[3+i*2])<<8 | uint16(data[4+i*2])
-	}
-	return values, nil
+// fail 记录一段读取失败
+func (r *reader) fail(start, count uint16, attempts int, err error) {
+	r.regs.Fail(FUNC_CODE_READ_HOLDING, start, count, attempts, err)
+	r.mark(start, count)
 }
 
-func crc16(data []byte) uint16 {
-	var crc uint16 = 0xFFFF
-	for _, b := range data {
-		crc ^= uint16(b)
-		for i := 0; i < 8; i++ {
-			if crc&0x0001 != 0 {
-				crc = (crc >> 1) ^ 0xA001
-			} else {
-				crc >>= 1
-			}
+// failRest 通信中断时把段内尚未取得值、也未记录失败的寄存器记为超时，
+// 同一寄存器只报告一次；attempts 计入第一段
+func (r *reader) failRest(start, count uint16, attempts int) {
+	end := uint32(start) + uint32(count)
+	for i := uint32(start); i < end; {
+		if r.seen[uint16(i)] {
+			i++
+			continue
 		}
+		j := i
+		for j < end && !r.seen[uint16(j)] {
+			j++
+		}
+		r.fail(uint16(i), uint16(j-i), attempts, modbus.ErrTimeout)
+		attempts = 0
+		i = j
 	}
-	return crc
 }
 
-func checkCRC(data []byte) bool {
-	if len(data) < 2 {
-		return false
+func (r *reader) mark(start, count uint16) {
+	if r.seen == nil {
+		r.seen = make(map[uint16]bool)
+	}
+	for i := uint32(start); i < uint32(start)+uint32(count); i++ {
+		r.seen[uint16(i)] = true
 	}
-	got := uint16(data[len(data)-2]) | uint16(data[len(data)-1])<<8
-	return crc16(data[:len(data)-2]) == got
 }
 
-func getConfig() DriverConfig {
-	def := DriverConfig{DeviceAddress: 1, FuncName: "read"}
-	var envelope struct {
-		Config map[string]string `json:"config"`
-	}
-	if err := pdk.InputJSON(&envelope); err != nil {
-		return def
+// revalidatePolls 解析 revalidate_polls，非法值取默认
+func revalidatePolls(cfg driver.Config) int {
+	n, err := strconv.Atoi(strings.TrimSpace(cfg.Raw["revalidate_polls"]))
+	if err != nil || n < 1 {
+		return defaultRevalidatePolls
 	}
+	return n
+}
 
-	cfg := def
-	if v := strings.TrimSpace(envelope.Config["device_address"]); v != "" {
-		if n, err := strconv.Atoi(v); err == nil {
-			cfg.DeviceAddress = n
-		}
-	}
-	if v := strings.TrimSpace(envelope.Config["func_name"]); v != "" {
-		cfg.FuncName = v
-	}
-	if v := strings.TrimSpace(envelope.Config["field_name"]); v != "" {
-		cfg.FieldName = v
+// 状态变化
+//
+// 每次轮询把各测点的取值保存在 Extism var 中，与上次比较生成 events；首次
+// 轮询（或驱动重载后）只记录基线。alarms / faults 与按楼层、设备类别的汇总
+// 统计本次读到取值的测点（good，以及取值不在 states 中的 out_of_range）；读取
+// 失败的测点不更新保存的取值、不产生事件，计入 stale，不沿用上次的状态（避免
+// 通信中断时持续报出或悄悄清除火警）。
+//
+// 面板状态码尚未按青鸟寄存器文档核对，点表不配置状态含义。现场核对后可在
+// config.states 中给出（如 0=正常;1=火警;2=故障;3=屏蔽），作用于点表未配置
+// 配置:states 的全部测点；也可在 points.xlsx 中逐行填写并 make points。
+// alarms / faults 按 config.alarm_codes / fault_codes 给出的取值统计，未给出时
+// 取状态含义为 火警 / 故障 的取值；两者都没有时为 null。
+
+const statesVar = "point_states"
+
+// panelPoints 点表加上 config.states 给出的状态含义，点表中已配置的行不变
+func panelPoints(cfg driver.Config) ([]point.Point, error) {
+	states, err := point.ParseStates(cfg.Raw["states"])
+	if err != nil {
+		return nil, errors.New("config.states: " + err.Error())
 	}
-	if v := strings.TrimSpace(envelope.Config["value"]); v != "" {
-		cfg.Value = v
+	if len(states) == 0 {
+		return pointConfig, nil
 	}
-	if v := strings.TrimSpace(envelope.Config["debug"]); v != "" {
-		cfg.Debug = v == "1" || strings.EqualFold(v, "true")
+	table := make([]point.Point, len(pointConfig))
+	for i, p := range pointConfig {
+		if len(p.States) == 0 {
+			p.States = states
+		}
+		table[i] = p
 	}
-	return cfg
+	return table, nil
 }
 
-func formatFloat(val float64, decimals int) string {
-	return strconv.FormatFloat(val, 'f', decimals, 64)
+// trackStates 与上次保存的取值比较生成事件并汇总（见 fire.Track），保存本次取值
+func trackStates(devAddr int, table []point.Point, points []map[string]interface{}, codes fire.Codes) ([]fire.Event, fire.Summary) {
+	key := driver.StateKey(statesVar, devAddr)
+	last := map[string]string{}
+	baseline := !driver.LoadState(key, &last)
+	events, sum := fire.Track(table, points, last, baseline, codes)
+	driver.SaveState(key, last)
+	return events, sum
 }
 
-type simpleErr string
-
-func (e simpleErr) Error() string { return string(e) }
-func errf(s string) error         { return simpleErr(s) }
-
-func outputJSON(v interface{}) {
-	b, _ := json.Marshal(v)
-	if len(b) == 0 {
-		b = []byte(`{"success":false,"error":"encode failed"}`)
+// describeTags 各测点的标签，以字段名为键
+func describeTags() map[string]fire.Tags {
+	tags := make(map[string]fire.Tags, len(pointConfig))
+	for _, p := range pointConfig {
+		tags[p.Field] = fire.TagsOf(p)
 	}
-	pdk.Output(b)
+	return tags
 }
 
-func logf(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	pdk.Log(pdk.LogDebug, msg)
+// 控制命令
+//
+// 转换卡是否开放复位、消音、屏蔽/启用，以及对应的线圈/寄存器地址因面板
+// 固件而异，驱动不设默认值，由 config.control 给出（见 modbus.Command）：
+//
+//	{"reset":   {"func_code": 5, "address": 1},
+//	 "silence": {"func_code": 5, "address": 2},
+//	 "isolate": {"func_code": 6, "offset": 1000, "value": 1},
+//	 "enable":  {"func_code": 6, "offset": 1000, "value": 0}}
+//
+// 整机命令（reset/silence）写 address；测点命令（isolate/enable）写
+// 测点地址 + offset，按保存的读取经验换算 0 基地址。复位、消音多为自动
+// 复位的脉冲线圈，缺省不读回；屏蔽/启用缺省读回同一线圈/寄存器核对，
+// 可按命令配置 "verify" 覆盖。
+// 每条命令须在 config.confirm 中给出口令，防止误发。
+
+// commands 支持的命令，值为是否按测点下发
+var commands = map[string]bool{
+	"reset":   false,
+	"silence": false,
+	"isolate": true,
+	"enable":  true,
 }
 
-func hexPreview(b []byte, n int, max int) string {
-	if n <= 0 {
-		return ""
+func runCommand(client *modbus.Client, cfg driver.Config, name string) map[string]interface{} {
+	fail := func(msg string) map[string]interface{} {
+		return map[string]interface{}{"success": false, "error": msg}
 	}
-	if n > len(b) {
-		n = len(b)
+	perPoint, ok := commands[name]
+	if !ok {
+		return fail("unsupported func_name: " + name)
 	}
-	if n > max {
-		n = max
+	// 屏蔽/启用缺省读回核对，复位/消音为脉冲线圈缺省不读回
+	cmd, err := modbus.LookupCommand(cfg.Raw["control"], name, perPoint, modbus.FuncWriteCoil, modbus.FuncWriteSingle)
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	token := name
+	var addr int
+	if perPoint {
+		p, ok := point.Find(pointConfig, cfg.FieldName)
+		if !ok {
+			return fail("unknown field_name: " + cfg.FieldName)
+		}
+		token = name + ":" + p.Field
+		addr = int(p.Address) + cmd.Offset
+		var plan readPlan
+		if driver.LoadState(driver.StateKey(planVar, cfg.DeviceAddress), &plan) && plan.ZeroBased {
+			addr--
+		}
+	} else {
+		if cmd.Address == nil {
+			return fail("control." + name + ": missing address")
+		}
+		addr = int(*cmd.Address)
+	}
+	if addr < 0 || addr > 0xFFFF {
+		return fail("control." + name + ": address out of range")
+	}
+	if err := modbus.Confirm(cfg.Raw["confirm"], token); err != nil {
+		return fail(err.Error())
+	}
+
+	res := client.Execute(byte(cfg.DeviceAddress), name, cmd, uint16(addr), cmd.WriteValue())
+	if perPoint {
+		res.Field = cfg.FieldName
 	}
-	return fmt.Sprintf("% X", b[:n])
+	return res.Output()
 }
 
 func main() {}
@@ -12,6 +12,16 @@
 //   - 组电压: TU, 地址0, 长度2, 表达式 v/10
 //   - 组电流: TI, 地址2, 长度2, 表达式 v/1000
 //   - 环境温度: T, 地址4, 长度1, 表达式 v/10-40
+//   - 组统计(计算值): 最高/最低/平均单体电压及单体号、压差、最高温度及单体号、
+//     平均内阻、电压/内阻偏差超限单体数，见 statsPoints
+//   - 按组电流 TI 判断静置/浮充/充电/放电，放电结束输出放电记录（events），
+//     跨轮询状态保存在 Extism var（charge_state@<从站地址>），见 trackCharge
+//   - SOC（安时积分，浮充/静置时校准）与 SOH（内阻相对投运基准的增长），见 estimate
+//   - 单体内阻基准与滚动平均保存在 Extism var（ir_trend@<从站地址>），输出变化率与
+//     告警，func_name=reset_ir_baseline 重建基准，见 trackIR
+//   - 充放电、SOC/SOH 与内阻趋势的计算在 battery 包中（可在本机测试），驱动只负责
+//     读取、配置与保存状态
+//   - 每组单体数、组数与各组地址偏移可配置（cells/strings/string_offsets），见 parseLayout
 //
 // Host 提供: serial_transceive
 //
@@ -20,11 +30,17 @@ package main
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
-	pdk "github.com/extism/go-pdk"
+	"github.com/gonglijing/xunjiFsu/drvs/battery"
+	"github.com/gonglijing/xunjiFsu/drvs/driver"
+	"github.com/gonglijing/xunjiFsu/drvs/modbus"
+	"github.com/gonglijing/xunjiFsu/drvs/point"
 )
 
 // =============================================================================
@@ -35,71 +51,258 @@ import (
 func serial_transceive(wPtr uint64, wSize uint64, rPtr uint64, rCap uint64, timeoutMs uint64) uint64
 
 // =============================================================================
-// 【固定不变】配置结构（网关传入）
+// 【用户修改】驱动版本
 // =============================================================================
-type DriverConfig struct {
-	DeviceAddress int    `json:"device_address"`
-	FuncName      string `json:"func_name"`
-	FieldName     string `json:"field_name"`
-	Value         string `json:"value"`
-	Debug         bool   `json:"debug"`
-}
+const DriverVersion = "1.0.0"
 
 // =============================================================================
-// 【用户修改】驱动版本
+// 【用户修改】点表配置
 // =============================================================================
-const DriverVersion = "1.0.0"
+// 【自动生成】pointgen
+// 由 points.xlsx 生成，请勿手工修改；修改点表后执行 make points
+var pointConfig = []point.Point{
+	{Field: "TU", FuncCode: modbus.FuncReadInput, Address: 0, Length: 2, DataType: point.TypeInt32, Scale: 0.1, Expr: "v/10", Decimals: 1, RW: "R", Unit: "V", Label: "组电压"},
+	{Field: "TI", FuncCode: modbus.FuncReadInput, Address: 2, Length: 2, DataType: point.TypeInt32, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "A", Label: "组电流"},
+	{Field: "T", FuncCode: modbus.FuncReadInput, Address: 4, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "环境温度"},
+	{Field: "U01", FuncCode: modbus.FuncReadInput, Address: 400, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池1#电压"},
+	{Field: "U02", FuncCode: modbus.FuncReadInput, Address: 401, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池2#电压"},
+	{Field: "U03", FuncCode: modbus.FuncReadInput, Address: 402, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池3#电压"},
+	{Field: "U04", FuncCode: modbus.FuncReadInput, Address: 403, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池4#电压"},
+	{Field: "U05", FuncCode: modbus.FuncReadInput, Address: 404, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池5#电压"},
+	{Field: "U06", FuncCode: modbus.FuncReadInput, Address: 405, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池6#电压"},
+	{Field: "U07", FuncCode: modbus.FuncReadInput, Address: 406, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池7#电压"},
+	{Field: "U08", FuncCode: modbus.FuncReadInput, Address: 407, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池8#电压"},
+	{Field: "U09", FuncCode: modbus.FuncReadInput, Address: 408, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池9#电压"},
+	{Field: "U10", FuncCode: modbus.FuncReadInput, Address: 409, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池10#电压"},
+	{Field: "U11", FuncCode: modbus.FuncReadInput, Address: 410, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池11#电压"},
+	{Field: "U12", FuncCode: modbus.FuncReadInput, Address: 411, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池12#电压"},
+	{Field: "U13", FuncCode: modbus.FuncReadInput, Address: 412, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池13#电压"},
+	{Field: "U14", FuncCode: modbus.FuncReadInput, Address: 413, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池14#电压"},
+	{Field: "U15", FuncCode: modbus.FuncReadInput, Address: 414, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池15#电压"},
+	{Field: "U16", FuncCode: modbus.FuncReadInput, Address: 415, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池16#电压"},
+	{Field: "U17", FuncCode: modbus.FuncReadInput, Address: 416, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池17#电压"},
+	{Field: "U18", FuncCode: modbus.FuncReadInput, Address: 417, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池18#电压"},
+	{Field: "U19", FuncCode: modbus.FuncReadInput, Address: 418, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池19#电压"},
+	{Field: "U20", FuncCode: modbus.FuncReadInput, Address: 419, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池20#电压"},
+	{Field: "U21", FuncCode: modbus.FuncReadInput, Address: 420, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池21#电压"},
+	{Field: "U22", FuncCode: modbus.FuncReadInput, Address: 421, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池22#电压"},
+	{Field: "U23", FuncCode: modbus.FuncReadInput, Address: 422, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池23#电压"},
+	{Field: "U24", FuncCode: modbus.FuncReadInput, Address: 423, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池24#电压"},
+	{Field: "U25", FuncCode: modbus.FuncReadInput, Address: 424, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池25#电压"},
+	{Field: "U26", FuncCode: modbus.FuncReadInput, Address: 425, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池26#电压"},
+	{Field: "U27", FuncCode: modbus.FuncReadInput, Address: 426, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池27#电压"},
+	{Field: "U28", FuncCode: modbus.FuncReadInput, Address: 427, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池28#电压"},
+	{Field: "U29", FuncCode: modbus.FuncReadInput, Address: 428, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池29#电压"},
+	{Field: "U30", FuncCode: modbus.FuncReadInput, Address: 429, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池30#电压"},
+	{Field: "U31", FuncCode: modbus.FuncReadInput, Address: 430, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池31#电压"},
+	{Field: "U32", FuncCode: modbus.FuncReadInput, Address: 431, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池32#电压"},
+	{Field: "U33", FuncCode: modbus.FuncReadInput, Address: 432, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池33#电压"},
+	{Field: "U34", FuncCode: modbus.FuncReadInput, Address: 433, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池34#电压"},
+	{Field: "U35", FuncCode: modbus.FuncReadInput, Address: 434, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池35#电压"},
+	{Field: "U36", FuncCode: modbus.FuncReadInput, Address: 435, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池36#电压"},
+	{Field: "U37", FuncCode: modbus.FuncReadInput, Address: 436, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池37#电压"},
+	{Field: "U38", FuncCode: modbus.FuncReadInput, Address: 437, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池38#电压"},
+	{Field: "U39", FuncCode: modbus.FuncReadInput, Address: 438, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池39#电压"},
+	{Field: "U40", FuncCode: modbus.FuncReadInput, Address: 439, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "V", Label: "电池40#电压"},
+	{Field: "T01", FuncCode: modbus.FuncReadInput, Address: 800, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池1#温度"},
+	{Field: "T02", FuncCode: modbus.FuncReadInput, Address: 801, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池2#温度"},
+	{Field: "T03", FuncCode: modbus.FuncReadInput, Address: 802, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池3#温度"},
+	{Field: "T04", FuncCode: modbus.FuncReadInput, Address: 803, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池4#温度"},
+	{Field: "T05", FuncCode: modbus.FuncReadInput, Address: 804, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池5#温度"},
+	{Field: "T06", FuncCode: modbus.FuncReadInput, Address: 805, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池6#温度"},
+	{Field: "T07", FuncCode: modbus.FuncReadInput, Address: 806, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池7#温度"},
+	{Field: "T08", FuncCode: modbus.FuncReadInput, Address: 807, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池8#温度"},
+	{Field: "T09", FuncCode: modbus.FuncReadInput, Address: 808, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池9#温度"},
+	{Field: "T10", FuncCode: modbus.FuncReadInput, Address: 809, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池10#温度"},
+	{Field: "T11", FuncCode: modbus.FuncReadInput, Address: 810, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池11#温度"},
+	{Field: "T12", FuncCode: modbus.FuncReadInput, Address: 811, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池12#温度"},
+	{Field: "T13", FuncCode: modbus.FuncReadInput, Address: 812, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池13#温度"},
+	{Field: "T14", FuncCode: modbus.FuncReadInput, Address: 813, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池14#温度"},
+	{Field: "T15", FuncCode: modbus.FuncReadInput, Address: 814, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池15#温度"},
+	{Field: "T16", FuncCode: modbus.FuncReadInput, Address: 815, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池16#温度"},
+	{Field: "T17", FuncCode: modbus.FuncReadInput, Address: 816, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池17#温度"},
+	{Field: "T18", FuncCode: modbus.FuncReadInput, Address: 817, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池18#温度"},
+	{Field: "T19", FuncCode: modbus.FuncReadInput, Address: 818, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池19#温度"},
+	{Field: "T20", FuncCode: modbus.FuncReadInput, Address: 819, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池20#温度"},
+	{Field: "T21", FuncCode: modbus.FuncReadInput, Address: 820, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池21#温度"},
+	{Field: "T22", FuncCode: modbus.FuncReadInput, Address: 821, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池22#温度"},
+	{Field: "T23", FuncCode: modbus.FuncReadInput, Address: 822, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池23#温度"},
+	{Field: "T24", FuncCode: modbus.FuncReadInput, Address: 823, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池24#温度"},
+	{Field: "T25", FuncCode: modbus.FuncReadInput, Address: 824, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池25#温度"},
+	{Field: "T26", FuncCode: modbus.FuncReadInput, Address: 825, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池26#温度"},
+	{Field: "T27", FuncCode: modbus.FuncReadInput, Address: 826, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池27#温度"},
+	{Field: "T28", FuncCode: modbus.FuncReadInput, Address: 827, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池28#温度"},
+	{Field: "T29", FuncCode: modbus.FuncReadInput, Address: 828, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池29#温度"},
+	{Field: "T30", FuncCode: modbus.FuncReadInput, Address: 829, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池30#温度"},
+	{Field: "T31", FuncCode: modbus.FuncReadInput, Address: 830, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池31#温度"},
+	{Field: "T32", FuncCode: modbus.FuncReadInput, Address: 831, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池32#温度"},
+	{Field: "T33", FuncCode: modbus.FuncReadInput, Address: 832, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池33#温度"},
+	{Field: "T34", FuncCode: modbus.FuncReadInput, Address: 833, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池34#温度"},
+	{Field: "T35", FuncCode: modbus.FuncReadInput, Address: 834, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池35#温度"},
+	{Field: "T36", FuncCode: modbus.FuncReadInput, Address: 835, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池36#温度"},
+	{Field: "T37", FuncCode: modbus.FuncReadInput, Address: 836, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池37#温度"},
+	{Field: "T38", FuncCode: modbus.FuncReadInput, Address: 837, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池38#温度"},
+	{Field: "T39", FuncCode: modbus.FuncReadInput, Address: 838, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池39#温度"},
+	{Field: "T40", FuncCode: modbus.FuncReadInput, Address: 839, Length: 1, DataType: point.TypeUint16, Scale: 0.1, Offset: -40, Expr: "v/10-40", Decimals: 1, RW: "R", Unit: "℃", Label: "电池40#温度"},
+	{Field: "IR01", FuncCode: modbus.FuncReadInput, Address: 1200, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池1#内阻"},
+	{Field: "IR02", FuncCode: modbus.FuncReadInput, Address: 1201, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池2#内阻"},
+	{Field: "IR03", FuncCode: modbus.FuncReadInput, Address: 1202, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池3#内阻"},
+	{Field: "IR04", FuncCode: modbus.FuncReadInput, Address: 1203, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池4#内阻"},
+	{Field: "IR05", FuncCode: modbus.FuncReadInput, Address: 1204, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池5#内阻"},
+	{Field: "IR06", FuncCode: modbus.FuncReadInput, Address: 1205, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池6#内阻"},
+	{Field: "IR07", FuncCode: modbus.FuncReadInput, Address: 1206, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池7#内阻"},
+	{Field: "IR08", FuncCode: modbus.FuncReadInput, Address: 1207, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池8#内阻"},
+	{Field: "IR09", FuncCode: modbus.FuncReadInput, Address: 1208, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池9#内阻"},
+	{Field: "IR10", FuncCode: modbus.FuncReadInput, Address: 1209, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池10#内阻"},
+	{Field: "IR11", FuncCode: modbus.FuncReadInput, Address: 1210, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池11#内阻"},
+	{Field: "IR12", FuncCode: modbus.FuncReadInput, Address: 1211, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池12#内阻"},
+	{Field: "IR13", FuncCode: modbus.FuncReadInput, Address: 1212, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池13#内阻"},
+	{Field: "IR14", FuncCode: modbus.FuncReadInput, Address: 1213, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池14#内阻"},
+	{Field: "IR15", FuncCode: modbus.FuncReadInput, Address: 1214, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池15#内阻"},
+	{Field: "IR16", FuncCode: modbus.FuncReadInput, Address: 1215, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池16#内阻"},
+	{Field: "IR17", FuncCode: modbus.FuncReadInput, Address: 1216, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池17#内阻"},
+	{Field: "IR18", FuncCode: modbus.FuncReadInput, Address: 1217, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池18#内阻"},
+	{Field: "IR19", FuncCode: modbus.FuncReadInput, Address: 1218, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池19#内阻"},
+	{Field: "IR20", FuncCode: modbus.FuncReadInput, Address: 1219, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池20#内阻"},
+	{Field: "IR21", FuncCode: modbus.FuncReadInput, Address: 1220, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池21#内阻"},
+	{Field: "IR22", FuncCode: modbus.FuncReadInput, Address: 1221, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池22#内阻"},
+	{Field: "IR23", FuncCode: modbus.FuncReadInput, Address: 1222, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池23#内阻"},
+	{Field: "IR24", FuncCode: modbus.FuncReadInput, Address: 1223, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池24#内阻"},
+	{Field: "IR25", FuncCode: modbus.FuncReadInput, Address: 1224, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池25#内阻"},
+	{Field: "IR26", FuncCode: modbus.FuncReadInput, Address: 1225, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池26#内阻"},
+	{Field: "IR27", FuncCode: modbus.FuncReadInput, Address: 1226, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池27#内阻"},
+	{Field: "IR28", FuncCode: modbus.FuncReadInput, Address: 1227, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池28#内阻"},
+	{Field: "IR29", FuncCode: modbus.FuncReadInput, Address: 1228, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池29#内阻"},
+	{Field: "IR30", FuncCode: modbus.FuncReadInput, Address: 1229, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池30#内阻"},
+	{Field: "IR31", FuncCode: modbus.FuncReadInput, Address: 1230, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池31#内阻"},
+	{Field: "IR32", FuncCode: modbus.FuncReadInput, Address: 1231, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池32#内阻"},
+	{Field: "IR33", FuncCode: modbus.FuncReadInput, Address: 1232, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池33#内阻"},
+	{Field: "IR34", FuncCode: modbus.FuncReadInput, Address: 1233, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池34#内阻"},
+	{Field: "IR35", FuncCode: modbus.FuncReadInput, Address: 1234, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池35#内阻"},
+	{Field: "IR36", FuncCode: modbus.FuncReadInput, Address: 1235, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池36#内阻"},
+	{Field: "IR37", FuncCode: modbus.FuncReadInput, Address: 1236, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池37#内阻"},
+	{Field: "IR38", FuncCode: modbus.FuncReadInput, Address: 1237, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池38#内阻"},
+	{Field: "IR39", FuncCode: modbus.FuncReadInput, Address: 1238, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池39#内阻"},
+	{Field: "IR40", FuncCode: modbus.FuncReadInput, Address: 1239, Length: 1, DataType: point.TypeUint16, Scale: 0.001, Expr: "v/1000", Decimals: 3, RW: "R", Unit: "Ω", Label: "电池40#内阻"},
+}
+
+// 读取分组
+var readBlocks = []modbus.Block{
+	{FuncCode: modbus.FuncReadInput, Start: 0, Count: 5},
+	{FuncCode: modbus.FuncReadInput, Start: 400, Count: 40},
+	{FuncCode: modbus.FuncReadInput, Start: 800, Count: 40},
+	{FuncCode: modbus.FuncReadInput, Start: 1200, Count: 40},
+}
+
+// 【自动生成】结束
 
 // =============================================================================
-// 【用户修改】协议定义
+// 【用户修改】配置项
 // =============================================================================
+// 缺省偏差门限
 const (
-	FUNC_CODE_READ_INPUT = 0x04
-
-	REG_GROUP_START = 0
-	REG_GROUP_LEN   = 5
+	DEFAULT_U_DEV  = 0.05 // V
+	DEFAULT_IR_DEV = 20.0 // %
+)
 
-	REG_U_START = 400
-	REG_U_LEN   = 40
+// 缺省充放电电流门限（A）
+const (
+	DEFAULT_IDLE_A      = 0.5
+	DEFAULT_FLOAT_A     = 3.0
+	DEFAULT_DISCHARGE_A = 1.0
+)
 
-	REG_T_START = 800
-	REG_T_LEN   = 40
+// 缺省 SOC/SOH 估算参数
+const (
+	DEFAULT_FLOAT_MIN = 60.0  // 分钟
+	DEFAULT_REST_MIN  = 120.0 // 分钟
+	DEFAULT_OCV_EMPTY = 1.98  // V，2V 铅酸单体
+	DEFAULT_OCV_FULL  = 2.13  // V
+)
 
-	REG_IR_START = 1200
-	REG_IR_LEN   = 40
+// 缺省内阻趋势参数
+const (
+	DEFAULT_IR_WINDOW     = 20
+	DEFAULT_IR_GROWTH     = 25.0 // %
+	DEFAULT_IR_MEDIAN_DEV = 30.0 // %
 )
 
+var configKeys = []point.ConfigKey{
+	{Key: "cells", Type: "int", Default: "40", Desc: "每组单体数 1~400"},
+	{Key: "strings", Type: "int", Default: "1", Desc: "电池组数 1~8"},
+	{Key: "string_offsets", Type: "json", Desc: "各组寄存器地址偏移（相对点表地址），如 [0,2000]；多组时必填"},
+	{Key: "idle_a", Type: "float", Default: "0.5", Desc: "组电流绝对值不超过此值视为静置（A）"},
+	{Key: "float_a", Type: "float", Default: "3", Desc: "充电电流不超过此值视为浮充（A）"},
+	{Key: "discharge_a", Type: "float", Default: "1", Desc: "放电电流超过此值视为放电（A）"},
+	{Key: "invert_current", Type: "bool", Default: "false", Desc: "组电流以放电为正时设为 true"},
+	{Key: "capacity_ah", Type: "float", Desc: "每组额定容量（Ah），SOC 估算必填"},
+	{Key: "float_min", Type: "float", Default: "60", Desc: "持续浮充多少分钟后 SOC 校准为 100%"},
+	{Key: "rest_min", Type: "float", Default: "120", Desc: "持续静置多少分钟后按开路电压校准 SOC"},
+	{Key: "ocv_empty", Type: "float", Default: "1.98", Desc: "SOC 0% 对应的单体开路电压（V）"},
+	{Key: "ocv_full", Type: "float", Default: "2.13", Desc: "SOC 100% 对应的单体开路电压（V）"},
+	{Key: "ir_baseline", Type: "json", Desc: "投运时平均单体内阻（同 IR 单位，大于 0），数值或每组一个的数组，SOH 估算必填"},
+	{Key: "ir_eol", Type: "float", Default: "50", Desc: "平均内阻增长多少 % 视为寿命终止（SOH 80%），须大于 0"},
+	{Key: "ir_window", Type: "int", Default: "20", Desc: "单体内阻滚动平均的采集次数"},
+	{Key: "ir_growth", Type: "float", Default: "25", Desc: "单体内阻高于自身基准多少 % 告警"},
+	{Key: "ir_median_dev", Type: "float", Default: "30", Desc: "单体内阻高于本组中位数多少 % 告警"},
+	{Key: "u_dev", Type: "float", Default: "0.05", Desc: "单体电压偏离平均值的门限（V）"},
+	{Key: "ir_dev", Type: "float", Default: "20", Desc: "单体内阻高于平均值的门限（%）"},
+}
+
 // =============================================================================
 // 【固定不变】驱动入口
 // =============================================================================
 //
 //go:wasmexport handle
 func handle() int32 {
-	defer func() {
-		if r := recover(); r != nil {
-			outputJSON(map[string]interface{}{"success": false, "error": "panic"})
-		}
-	}()
+	defer driver.Recover()
 
-	cfg := getConfig()
-	points := readAllPoints(cfg.DeviceAddress, cfg.Debug)
+	cfg := driver.GetConfig()
+	strs, err := parseLayout(cfg)
+	if err != nil {
+		driver.OutputJSON(map[string]interface{}{"success": false, "error": err.Error()})
+		return 0
+	}
+	soh, err := battery.ParseSOH(cfg.Raw["ir_baseline"], cfg.Raw["ir_eol"], len(strs))
+	if err != nil {
+		driver.OutputJSON(map[string]interface{}{"success": false, "error": err.Error()})
+		return 0
+	}
+	if name := strings.ToLower(cfg.FuncName); name != "read" {
+		driver.OutputJSON(runCommand(cfg, strs, name))
+		return 0
+	}
 
-	outputJSON(map[string]interface{}{
-		"success": true,
-		"points":  points,
-	})
+	client := driver.NewRTUClient(serial_transceive, cfg.Debug)
+	points, regs := readAllPoints(client, byte(cfg.DeviceAddress), strs)
+	for _, b := range strs {
+		points = append(points, stringStats(regs, cfg, b)...)
+	}
+	charge, events := trackCharge(regs, cfg, strs, soh, time.Now().Unix())
+	points = append(points, charge...)
+	points = append(points, trackIR(regs, cfg, strs)...)
+
+	result := point.Result(points, regs.Errors())
+	result["events"] = events
+	driver.OutputJSON(result)
 	return 0
 }
 
 // =============================================================================
-// 【固定不变】描述可写字段
+// 【固定不变】描述点表
 // =============================================================================
 //
 //go:wasmexport describe
 func describe() int32 {
-	outputJSON(map[string]interface{}{
+	// 布局配置有误时按点表（单组 40 节）描述
+	strs, err := parseLayout(driver.GetConfig())
+	if err != nil {
+		strs = []group{{Points: pointConfig}}
+	}
+	driver.OutputJSON(map[string]interface{}{
 		"success": true,
-		"data":    map[string]string{},
+		"data":    point.Describe(DriverVersion, "modbus-rtu", allPoints(strs), configKeys...),
+		"derived": describeDerived(strs),
 	})
 	return 0
 }
@@ -110,258 +313,615 @@ func describe() int32 {
 //
 //go:wasmexport version
 func version() int32 {
-	outputJSON(map[string]interface{}{
-		"success": true,
-		"data": map[string]string{
-			"version": DriverVersion,
-		},
-	})
+	driver.OutputVersion(DriverVersion)
 	return 0
 }
 
 // =============================================================================
-// 【用户修改】读取所有测点
+// 【用户修改】电池组布局
 // =============================================================================
-func readAllPoints(devAddr int, debug bool) []map[string]interface{} {
-	points := make([]map[string]interface{}, 0, 123)
-
-	// 组级参数: TU(2寄存器), TI(2寄存器), T(1寄存器)
-	if values := readMultipleRegs(byte(devAddr), REG_GROUP_START, REG_GROUP_LEN, debug); values != nil && len(values) >= 5 {
-		tuRaw := combineTwoRegs(values[0], values[1])
-		tiRaw := combineTwoRegs(values[2], values[3])
-		tRaw := int64(values[4])
-
-		points = append(points, makePointValue("TU", float64(tuRaw)/10.0, 1, "R", "V", "组电压"))
-		points = append(points, makePointValue("TI", float64(tiRaw)/1000.0, 3, "R", "A", "组电流"))
-		points = append(points, makePointValue("T", float64(tRaw)/10.0-40.0, 1, "R", "℃", "环境温度"))
-	}
-
-	// 电池1~40电压 U01~U40: v/1000
-	if values := readMultipleRegs(byte(devAddr), REG_U_START, REG_U_LEN, debug); values != nil && len(values) >= REG_U_LEN {
-		for i := 0; i < REG_U_LEN; i++ {
-			idx := i + 1
-			field := fmt.Sprintf("U%02d", idx)
-			label := fmt.Sprintf("电池%d#电压", idx)
-			points = append(points, makePointValue(field, float64(values[i])/1000.0, 3, "R", "V", label))
+//
+// 点表为一组 40 节的寄存器布局：组参数 TU/TI/T，单体电压/温度/内阻各占一段
+// （基址 400/800/1200，按单体号连续）。cells 改变每组单体数，超过 40 节时
+// 按 01 号单体的地址与标签顺延；strings 与 string_offsets 给出组数和各组
+// 相对点表地址的偏移。单组时字段名同点表（U07），多组时加组前缀（G2_U07）。
+
+// 布局上限：单体寄存器段间隔 400
+const (
+	MAX_CELLS   = 400
+	MAX_STRINGS = 8
+)
+
+// cellKinds 单体测点的字段名前缀：电压、温度、内阻
+var cellKinds = []string{"U", "T", "IR"}
+
+// group 一组电池
+type group struct {
+	No     int           // 组号，从 1 开始
+	Prefix string        // 字段名前缀，单组时为空
+	Points []point.Point // 组参数与单体测点，地址已加组偏移
+}
+
+// parseLayout 按 cells / strings / string_offsets 生成各组测点
+func parseLayout(cfg driver.Config) ([]group, error) {
+	cells, err := configInt(cfg, "cells", 40, 1, MAX_CELLS)
+	if err != nil {
+		return nil, err
+	}
+	n, err := configInt(cfg, "strings", 1, 1, MAX_STRINGS)
+	if err != nil {
+		return nil, err
+	}
+	offsets := []int{0}
+	if raw := strings.TrimSpace(cfg.Raw["string_offsets"]); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &offsets); err != nil {
+			return nil, errors.New("string_offsets: want a JSON array of integers")
 		}
 	}
+	if len(offsets) != n {
+		return nil, errors.New("string_offsets: want " + strconv.Itoa(n) + " offsets")
+	}
 
-	// 电池1~40温度 T01~T40: v/10-40
-	if values := readMultipleRegs(byte(devAddr), REG_T_START, REG_T_LEN, debug); values != nil && len(values) >= REG_T_LEN {
-		for i := 0; i < REG_T_LEN; i++ {
-			idx := i + 1
-			field := fmt.Sprintf("T%02d", idx)
-			label := fmt.Sprintf("电池%d#温度", idx)
-			points = append(points, makePointValue(field, float64(values[i])/10.0-40.0, 1, "R", "℃", label))
+	strs := make([]group, n)
+	for g := range strs {
+		b := group{No: g + 1}
+		if n > 1 {
+			b.Prefix = "G" + strconv.Itoa(g+1) + "_"
 		}
+		for _, p := range stringTemplate(cells) {
+			addr := int(p.Address) + offsets[g]
+			if addr < 0 || addr+int(p.Count()) > 0x10000 {
+				return nil, errors.New("string_offsets[" + strconv.Itoa(g) + "]: " + p.Field + " address out of range")
+			}
+			p.Address = uint16(addr)
+			if n > 1 {
+				p.Field = b.Prefix + p.Field
+				p.Label = "第" + strconv.Itoa(g+1) + "组" + p.Label
+			}
+			b.Points = append(b.Points, p)
+		}
+		strs[g] = b
 	}
+	return strs, nil
+}
 
-	// 电池1~40内阻 IR01~IR40: v/1000
-	if values := readMultipleRegs(byte(devAddr), REG_IR_START, REG_IR_LEN, debug); values != nil && len(values) >= REG_IR_LEN {
-		for i := 0; i < REG_IR_LEN; i++ {
-			idx := i + 1
-			field := fmt.Sprintf("IR%02d", idx)
-			label := fmt.Sprintf("电池%d#内阻", idx)
-			points = append(points, makePointValue(field, float64(values[i])/1000.0, 3, "R", "Ω", label))
+// stringTemplate 一组 cells 节的测点（未加偏移）：点表中的组参数，之后按电压、
+// 温度、内阻依次为 1~cells 号单体，超出点表的单体按 01 号顺延
+func stringTemplate(cells int) []point.Point {
+	var out []point.Point
+	for _, p := range pointConfig {
+		if _, _, ok := cellOf(p.Field); !ok {
+			out = append(out, p)
 		}
 	}
+	for _, kind := range cellKinds {
+		first, ok := point.Find(pointConfig, kind+"01")
+		if !ok {
+			continue
+		}
+		for no := 1; no <= cells; no++ {
+			field := kind + battery.CellNo(no)
+			p, ok := point.Find(pointConfig, field)
+			if !ok {
+				p = first
+				p.Field = field
+				p.Address = first.Address + uint16(no-1)
+				p.Label = strings.Replace(first.Label, "1#", strconv.Itoa(no)+"#", 1)
+			}
+			out = append(out, p)
+		}
+	}
+	return out
+}
 
-	return points
+// cellOf 解析单体字段名（不含组前缀），如 IR07 → IR, 7
+func cellOf(field string) (kind string, no int, ok bool) {
+	for _, k := range cellKinds {
+		if !strings.HasPrefix(field, k) {
+			continue
+		}
+		if n, err := strconv.Atoi(field[len(k):]); err == nil && n > 0 {
+			return k, n, true
+		}
+	}
+	return "", 0, false
 }
 
-func makePointValue(field string, value float64, decimals int, rw, unit, label string) map[string]interface{} {
-	return map[string]interface{}{
-		"field_name": field,
-		"value":      formatFloat(value, decimals),
-		"rw":         rw,
-		"unit":       unit,
-		"label":      label,
+// allPoints 各组测点依次排列
+func allPoints(strs []group) []point.Point {
+	var out []point.Point
+	for _, b := range strs {
+		out = append(out, b.Points...)
 	}
+	return out
 }
 
-func combineTwoRegs(high uint16, low uint16) int64 {
-	v := (uint32(high) << 16) | uint32(low)
-	return int64(int32(v))
+// configInt 解析整数配置，缺省取 def，超出 [min, max] 时报错
+func configInt(cfg driver.Config, key string, def, min, max int) (int, error) {
+	v := strings.TrimSpace(cfg.Raw[key])
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min || n > max {
+		return 0, errors.New(key + " must be " + strconv.Itoa(min) + "~" + strconv.Itoa(max))
+	}
+	return n, nil
 }
 
 // =============================================================================
-// 【固定不变】Modbus RTU 通信函数
+// 【用户修改】读取所有测点
 // =============================================================================
+// 缺省布局（单组 40 节、无偏移）使用生成的 readBlocks，否则按布局重新分组
+func readAllPoints(client *modbus.Client, devAddr byte, strs []group) ([]map[string]interface{}, *point.Registers) {
+	points := allPoints(strs)
+	blocks := readBlocks
+	if len(points) != len(pointConfig) || points[0].Address != pointConfig[0].Address {
+		// 参数为常量，不会出错
+		blocks, _ = point.Plan(points, modbus.MaxReadCount, 0)
+	}
+
+	regs := point.ReadBlocks(client, devAddr, blocks)
+	return regs.Collect(points), regs
+}
 
-func readMultipleRegs(devAddr byte, startReg uint16, count uint16, debug bool) []uint16 {
-	req := buildReadFrame(devAddr, startReg, count)
-	if debug {
-		logf("rtu req=% X", req)
+// =============================================================================
+// 【用户修改】组统计
+// =============================================================================
+//
+// 每组按单体电压 U、温度 T、内阻 IR 计算组统计，作为附加测点输出在点表测点
+// 之后，多组时字段名同样加组前缀（G2_U_MAX）。读取失败的单体不参与统计；
+// 某类单体全部失败时对应的统计测点 quality 为 comm_fail。
+//
+// 偏差超限：|单体电压 - 平均电压| > u_dev（V），或单体内阻高于平均内阻
+// ir_dev（%）以上。
+
+// statsPoints 组统计测点，不对应寄存器，仅用于格式化输出
+var statsPoints = []point.Point{
+	{Field: "U_MAX", Decimals: 3, RW: "R", Unit: "V", Label: "最高单体电压"},
+	{Field: "U_MAX_NO", RW: "R", Label: "最高电压单体号"},
+	{Field: "U_MIN", Decimals: 3, RW: "R", Unit: "V", Label: "最低单体电压"},
+	{Field: "U_MIN_NO", RW: "R", Label: "最低电压单体号"},
+	{Field: "U_AVG", Decimals: 3, RW: "R", Unit: "V", Label: "平均单体电压"},
+	{Field: "U_DIFF", Decimals: 3, RW: "R", Unit: "V", Label: "单体压差"},
+	{Field: "T_MAX", Decimals: 1, RW: "R", Unit: "℃", Label: "最高单体温度"},
+	{Field: "T_MAX_NO", RW: "R", Label: "最高温度单体号"},
+	{Field: "IR_AVG", Decimals: 3, RW: "R", Unit: "Ω", Label: "平均单体内阻"},
+	{Field: "U_DEV_CNT", RW: "R", Label: "电压偏差超限单体数"},
+	{Field: "IR_DEV_CNT", RW: "R", Label: "内阻偏差超限单体数"},
+}
+
+// cellValues 取一组中 kind 类单体的工程值，按单体号顺序，读取失败的单体跳过
+func cellValues(regs *point.Registers, b group, kind string) []battery.Cell {
+	var out []battery.Cell
+	for _, p := range b.Points {
+		k, no, ok := cellOf(strings.TrimPrefix(p.Field, b.Prefix))
+		if !ok || k != kind {
+			continue
+		}
+		words, err := regs.Words(p)
+		if err != nil {
+			continue
+		}
+		if v, err := p.Value(words); err == nil {
+			out = append(out, battery.Cell{No: no, Value: v})
+		}
 	}
+	sort.Slice(out, func(i, j int) bool { return out[i].No < out[j].No })
+	return out
+}
 
-	resp, n := serialTransceive(req, int(count)*2+5, 1000)
-	if debug {
-		logf("rtu n=%d resp=%s", n, hexPreview(resp, n, 24))
+// stringStats 计算一组的统计测点，顺序同 statsPoints
+func stringStats(regs *point.Registers, cfg driver.Config, b group) []map[string]interface{} {
+	values := map[string]float64{}
+	if u := cellValues(regs, b, "U"); len(u) > 0 {
+		s := battery.StatsOf(u)
+		limit := configFloat(cfg, "u_dev", DEFAULT_U_DEV)
+		n := 0
+		for _, c := range u {
+			if math.Abs(c.Value-s.Mean) > limit {
+				n++
+			}
+		}
+		values["U_MAX"], values["U_MAX_NO"] = s.Max, float64(s.MaxNo)
+		values["U_MIN"], values["U_MIN_NO"] = s.Min, float64(s.MinNo)
+		values["U_AVG"], values["U_DIFF"] = s.Mean, s.Max-s.Min
+		values["U_DEV_CNT"] = float64(n)
 	}
-	if n <= 0 {
-		return nil
+	if t := cellValues(regs, b, "T"); len(t) > 0 {
+		s := battery.StatsOf(t)
+		values["T_MAX"], values["T_MAX_NO"] = s.Max, float64(s.MaxNo)
 	}
-
-	values, err := parseReadResponse(resp[:n], devAddr)
-	if err != nil || len(values) < int(count) {
-		if debug {
-			logf("parse err=%v", err)
+	if ir := cellValues(regs, b, "IR"); len(ir) > 0 {
+		s := battery.StatsOf(ir)
+		limit := s.Mean * (1 + configFloat(cfg, "ir_dev", DEFAULT_IR_DEV)/100)
+		n := 0
+		for _, c := range ir {
+			if c.Value > limit {
+				n++
+			}
 		}
-		return nil
+		values["IR_AVG"], values["IR_DEV_CNT"] = s.Mean, float64(n)
 	}
 
-	return values
+	out := make([]map[string]interface{}, 0, len(statsPoints))
+	for _, p := range b.derived(statsPoints) {
+		if v, ok := values[strings.TrimPrefix(p.Field, b.Prefix)]; ok {
+			out = append(out, p.Output(v))
+		} else {
+			out = append(out, p.Invalid(point.QualityCommFail, "no cell read"))
+		}
+	}
+	return out
 }
 
-func serialTransceive(req []byte, respLen int, timeoutMs int) ([]byte, int) {
-	if len(req) == 0 || respLen <= 0 {
-		return nil, 0
+// derived 本组的计算测点，多组时字段名与标签带组号
+func (b group) derived(points []point.Point) []point.Point {
+	out := make([]point.Point, len(points))
+	for i, p := range points {
+		if b.Prefix != "" {
+			p.Field = b.Prefix + p.Field
+			p.Label = "第" + strconv.Itoa(b.No) + "组" + p.Label
+		}
+		out[i] = p
 	}
+	return out
+}
 
-	reqMem := pdk.AllocateBytes(req)
-	defer reqMem.Free()
-	respMem := pdk.Allocate(respLen)
-	defer respMem.Free()
-
-	n := int(serial_transceive(
-		reqMem.Offset(), uint64(len(req)),
-		respMem.Offset(), uint64(respLen),
-		uint64(timeoutMs),
-	))
-	if n <= 0 {
-		return nil, n
+// configFloat 解析浮点配置，缺省或非法时取 def
+func configFloat(cfg driver.Config, key string, def float64) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(cfg.Raw[key]), 64)
+	if err != nil || v < 0 {
+		return def
 	}
-	if n > respLen {
-		n = respLen
+	return v
+}
+
+// describeDerived 各组计算测点的字段名、名称与单位
+func describeDerived(strs []group) []map[string]string {
+	var out []map[string]string
+	for _, b := range strs {
+		for _, list := range [][]point.Point{b.derived(statsPoints), b.derived(chargePoints), b.derived(estimatePoints), b.trendPoints()} {
+			for _, p := range list {
+				out = append(out, map[string]string{"field_name": p.Field, "label": p.Label, "unit": p.Unit})
+			}
+		}
 	}
+	return out
+}
 
-	resp := make([]byte, n)
-	mem := pdk.NewMemory(respMem.Offset(), uint64(n))
-	mem.Load(resp)
-	return resp, n
+// =============================================================================
+// 【用户修改】充放电状态
+// =============================================================================
+//
+// 按组电流 TI（充电为正，invert_current=true 时取反）判断每组静置/浮充/充电/
+// 放电状态并累计放电容量，规则见 battery.State.Update，门限取 idle_a /
+// float_a / discharge_a。离开放电时输出一条放电记录（events），停电即自动
+// 成为一次放电测试记录。各组状态保存在 Extism var 中，驱动重载后从下一次
+// 采集重新开始。
+
+const chargeVar = "charge_state"
+
+// StatesCharge 充放电状态含义
+var StatesCharge = []point.State{
+	{Value: battery.StateIdle, Label: "静置"},
+	{Value: battery.StateFloat, Label: "浮充"},
+	{Value: battery.StateCharge, Label: "充电"},
+	{Value: battery.StateDischarge, Label: "放电"},
 }
 
-func buildReadFrame(addr byte, start uint16, qty uint16) []byte {
-	req := make([]byte, 8)
-	req[0] = addr
-	req[1] = FUNC_CODE_READ_INPUT
-	req[2], req[3] = byte(start>>8), byte(start)
-	req[4], req[5] = byte(qty>>8), byte(qty)
-	crc := crc16(req[:6])
-	req[6], req[7] = byte(crc), byte(crc>>8)
-	return req
+// chargePoints 充放电测点；DIS_* 放电中为本次累计值，否则为上次放电记录
+var chargePoints = []point.Point{
+	{Field: "STATE", RW: "R", Label: "充放电状态", States: StatesCharge},
+	{Field: "DIS_TIME", RW: "R", Unit: "s", Label: "放电时长"},
+	{Field: "DIS_AH", Decimals: 3, RW: "R", Unit: "Ah", Label: "放电容量"},
+	{Field: "DIS_U_MIN", Decimals: 3, RW: "R", Unit: "V", Label: "放电最低单体电压"},
 }
 
-func parseReadResponse(data []byte, addr byte) ([]uint16, error) {
-	if len(data) < 5 || data[0] != addr || data[1] != FUNC_CODE_READ_INPUT {
-		return nil, errf("invalid response")
-	}
-	byteCnt := int(data[2])
-	if byteCnt < 2 || len(data) < 3+byteCnt+2 {
-		return nil, errf("byte count mismatch")
-	}
-	if !checkCRC(data[:3+byteCnt+2]) {
-		return nil, errf("crc error")
-	}
+// dischargeEvent 放电结束时输出的记录
+type dischargeEvent struct {
+	Type     string `json:"type"` // "discharge"
+	String   int    `json:"string"`
+	Duration int64  `json:"duration"` // 秒
+	battery.Discharge
+}
 
-	values := make([]uint16, byteCnt/2)
-	for i := 0; i < len(values); i++ {
-		values[i] = uint16(data[3+i*2])<<8 | uint16(data[4+i*2])
+// chargeParams 充放电门限与 SOC 估算参数，缺省或非法时取默认值
+func chargeParams(cfg driver.Config) battery.Params {
+	return battery.Params{
+		IdleA:      configFloat(cfg, "idle_a", DEFAULT_IDLE_A),
+		FloatA:     configFloat(cfg, "float_a", DEFAULT_FLOAT_A),
+		DischargeA: configFloat(cfg, "discharge_a", DEFAULT_DISCHARGE_A),
+		CapacityAh: configFloat(cfg, "capacity_ah", 0),
+		FloatMin:   configFloat(cfg, "float_min", DEFAULT_FLOAT_MIN),
+		RestMin:    configFloat(cfg, "rest_min", DEFAULT_REST_MIN),
+		OCVEmpty:   configFloat(cfg, "ocv_empty", DEFAULT_OCV_EMPTY),
+		OCVFull:    configFloat(cfg, "ocv_full", DEFAULT_OCV_FULL),
 	}
-	return values, nil
 }
 
-func crc16(data []byte) uint16 {
-	var crc uint16 = 0xFFFF
-	for _, b := range data {
-		crc ^= uint16(b)
-		for i := 0; i < 8; i++ {
-			if crc&0x0001 != 0 {
-				crc = (crc >> 1) ^ 0xA001
-			} else {
-				crc >>= 1
+// trackCharge 判断各组充放电状态并累计放电、估算 SOC/SOH，返回各组的充放电与
+// 估算测点，以及本次结束的放电记录
+func trackCharge(regs *point.Registers, cfg driver.Config, strs []group, soh battery.SOH, now int64) ([]map[string]interface{}, []dischargeEvent) {
+	key := driver.StateKey(chargeVar, cfg.DeviceAddress)
+	states := map[string]*battery.State{}
+	driver.LoadState(key, &states)
+
+	params := chargeParams(cfg)
+	invert := driver.ParseBool(strings.TrimSpace(cfg.Raw["invert_current"]))
+	var out []map[string]interface{}
+	events := []dischargeEvent{}
+	for _, b := range strs {
+		st := states[strconv.Itoa(b.No)]
+		if st == nil {
+			st = &battery.State{}
+			states[strconv.Itoa(b.No)] = st
+		}
+		current, err := b.valueOf(regs, "TI")
+		if err == nil {
+			if invert {
+				current = -current
 			}
+			if d := st.Update(params, current, cellValues(regs, b, "U"), now); d != nil {
+				events = append(events, dischargeEvent{Type: "discharge", String: b.No, Duration: d.End - d.Start, Discharge: *d})
+			}
+		}
+		out = append(out, b.chargeOutput(st, err)...)
+		out = append(out, b.estimate(st, regs, params, soh, err, now)...)
+	}
+	driver.SaveState(key, states)
+	return out, events
+}
+
+// chargeOutput 本组的充放电测点，顺序同 chargePoints；err 为读取组电流的错误
+func (b group) chargeOutput(st *battery.State, err error) []map[string]interface{} {
+	pts := b.derived(chargePoints)
+	out := make([]map[string]interface{}, 0, len(pts))
+	if err != nil {
+		out = append(out, pts[0].Invalid(point.Classify(err), err.Error()))
+	} else {
+		out = append(out, pts[0].Output(float64(st.State)))
+	}
+
+	d := st.Active
+	if d == nil {
+		d = st.Last
+	}
+	for _, p := range pts[1:] {
+		switch {
+		case d == nil:
+			out = append(out, p.Invalid(point.QualityNoData, "no discharge recorded"))
+		case strings.HasSuffix(p.Field, "DIS_TIME"):
+			out = append(out, p.Output(float64(d.End-d.Start)))
+		case strings.HasSuffix(p.Field, "DIS_AH"):
+			out = append(out, p.Output(d.Ah))
+		case d.MinCell == 0:
+			out = append(out, p.Invalid(point.QualityNoData, "no cell voltage read"))
+		default:
+			out = append(out, p.Output(d.MinU))
 		}
 	}
-	return crc
+	return out
 }
 
-func checkCRC(data []byte) bool {
-	if len(data) < 2 {
-		return false
+// valueOf 本组 field（不含组前缀）测点的工程值
+func (b group) valueOf(regs *point.Registers, field string) (float64, error) {
+	p, ok := point.Find(b.Points, b.Prefix+field)
+	if !ok {
+		return 0, errors.New(field + " not in point table")
+	}
+	words, err := regs.Words(p)
+	if err != nil {
+		return 0, err
 	}
-	got := uint16(data[len(data)-2]) | uint16(data[len(data)-1])<<8
-	return crc16(data[:len(data)-2]) == got
+	return p.Value(words)
 }
 
 // =============================================================================
-// 【固定不变】工具函数
+// 【用户修改】SOC / SOH 估算
 // =============================================================================
+//
+// SOC 按组电流安时积分，持续浮充 float_min 分钟置 100%、持续静置 rest_min
+// 分钟按平均单体电压在 ocv_empty~ocv_full 间折算，见 battery.State.Update；
+// capacity_ah 未配置时不输出。
+//
+// SOH 按平均单体内阻相对投运基准 ir_baseline 的增长折算，见 battery.SOH。
+// ir_baseline / ir_eol 在读取前校验，基准或 ir_eol 不大于 0 时不读取、直接报错。
+// 本次未读到内阻时不重复输出上次结果：SOH 与 SOH_CONF 为 no_data，SOH 另带
+// 上次结果 last_value 与其时效 age（秒）。
+
+// SOC 可信度
+var StatesSOCConf = []point.State{
+	{Value: battery.ConfNone, Label: "未校准"},
+	{Value: battery.ConfPartial, Label: "推算"},
+	{Value: battery.ConfFull, Label: "已校准"},
+}
 
-func getConfig() DriverConfig {
-	def := DriverConfig{DeviceAddress: 1, FuncName: "read"}
-	var envelope struct {
-		Config map[string]string `json:"config"`
-	}
-	if err := pdk.InputJSON(&envelope); err != nil {
-		return def
-	}
+// SOH 可信度
+var StatesSOHConf = []point.State{
+	{Value: battery.ConfNone, Label: "无基准"},
+	{Value: battery.ConfPartial, Label: "部分单体"},
+	{Value: battery.ConfFull, Label: "全部单体"},
+}
 
-	cfg := def
-	if v := strings.TrimSpace(envelope.Config["device_address"]); v != "" {
-		if n, err := strconv.Atoi(v); err == nil {
-			cfg.DeviceAddress = n
-		}
-	}
-	if v := strings.TrimSpace(envelope.Config["func_name"]); v != "" {
-		cfg.FuncName = v
+// estimatePoints SOC/SOH 测点
+var estimatePoints = []point.Point{
+	{Field: "SOC", Decimals: 1, RW: "R", Unit: "%", Label: "荷电状态"},
+	{Field: "SOC_CONF", RW: "R", Label: "荷电状态可信度", States: StatesSOCConf},
+	{Field: "SOH", Decimals: 1, RW: "R", Unit: "%", Label: "健康状态"},
+	{Field: "SOH_CONF", RW: "R", Label: "健康状态可信度", States: StatesSOHConf},
+}
+
+// estimate 本组的 SOC/SOH 测点，顺序同 estimatePoints；err 为读取组电流的错误
+func (b group) estimate(st *battery.State, regs *point.Registers, params battery.Params, soh battery.SOH, err error, now int64) []map[string]interface{} {
+	pts := b.derived(estimatePoints)
+	out := make([]map[string]interface{}, 0, len(pts))
+	switch {
+	case params.CapacityAh <= 0:
+		out = append(out, pts[0].Invalid(point.QualityNoData, "capacity_ah not configured"), pts[1].Output(battery.ConfNone))
+	case err != nil:
+		out = append(out, pts[0].Invalid(point.Classify(err), err.Error()), pts[1].Output(float64(st.SOCConf)))
+	default:
+		out = append(out, pts[0].Output(*st.SOC), pts[1].Output(float64(st.SOCConf)))
 	}
-	if v := strings.TrimSpace(envelope.Config["field_name"]); v != "" {
-		cfg.FieldName = v
+
+	if !soh.Configured() {
+		return append(out, pts[2].Invalid(point.QualityNoData, "ir_baseline not configured"), pts[3].Output(battery.ConfNone))
 	}
-	if v := strings.TrimSpace(envelope.Config["value"]); v != "" {
-		cfg.Value = v
+	ir := cellValues(regs, b, "IR")
+	if !st.EstimateSOH(soh, b.No, ir, now) {
+		if st.SOH == nil {
+			return append(out, pts[2].Invalid(point.QualityCommFail, "no cell read"), pts[3].Output(battery.ConfNone))
+		}
+		const reason = "no resistance read this poll"
+		last := pts[2].Invalid(point.QualityNoData, reason)
+		last["last_value"] = pts[2].Format(*st.SOH)
+		if st.SOHTime > 0 {
+			last["age"] = now - st.SOHTime
+		}
+		return append(out, last, pts[3].Invalid(point.QualityNoData, reason))
 	}
-	if v := strings.TrimSpace(envelope.Config["debug"]); v != "" {
-		cfg.Debug = v == "1" || strings.EqualFold(v, "true")
+	conf := battery.ConfFull
+	if len(ir) < b.cells("IR") {
+		conf = battery.ConfPartial
 	}
-	return cfg
+	return append(out, pts[2].Output(*st.SOH), pts[3].Output(float64(conf)))
 }
 
-func formatFloat(val float64, decimals int) string {
-	return strconv.FormatFloat(val, 'f', decimals, 64)
+// cells 本组 kind 类单体测点数
+func (b group) cells(kind string) int {
+	n := 0
+	for _, p := range b.Points {
+		if k, _, ok := cellOf(strings.TrimPrefix(p.Field, b.Prefix)); ok && k == kind {
+			n++
+		}
+	}
+	return n
 }
 
-type simpleErr string
+// =============================================================================
+// 【用户修改】内阻趋势
+// =============================================================================
+//
+// 单体内阻只有看趋势才有意义。每个单体保存基准与滚动平均，变化率
+// IRTnn = (滚动平均 / 基准 - 1) × 100%；告警 IRAnn 为内阻增长（高于自身基准
+// ir_growth%）与偏离中位数（高于本组中位数 ir_median_dev%），规则见
+// battery.IRTrend。尚未建立基准或本次未读到的单体输出 quality=no_data
+// （内阻测点本身另有读取质量）。
+//
+// 更换电池或维护后以 func_name=reset_ir_baseline 重建基准（field_name 指定
+// 单个内阻测点，如 G2_IR07，缺省为全部单体），下一次采集重新建立。
 
-func (e simpleErr) Error() string { return string(e) }
-func errf(s string) error         { return simpleErr(s) }
+const trendVar = "ir_trend"
 
-func outputJSON(v interface{}) {
-	b, _ := json.Marshal(v)
-	if len(b) == 0 {
-		b = []byte(`{"success":false,"error":"encode failed"}`)
+// StatesIRAlarm 单体内阻告警含义
+var StatesIRAlarm = []point.State{
+	{Value: 0, Label: "正常"},
+	{Value: battery.IRAlarmGrowth, Label: "内阻增长超限"},
+	{Value: battery.IRAlarmMedian, Label: "偏离中位数超限"},
+	{Value: battery.IRAlarmGrowth | battery.IRAlarmMedian, Label: "增长且偏离超限"},
+}
+
+// irCells 本组内阻测点的单体号与测点，按点表顺序
+func (b group) irCells() ([]int, []point.Point) {
+	var nos []int
+	var pts []point.Point
+	for _, p := range b.Points {
+		if kind, no, ok := cellOf(strings.TrimPrefix(p.Field, b.Prefix)); ok && kind == "IR" {
+			nos, pts = append(nos, no), append(pts, p)
+		}
 	}
-	pdk.Output(b)
+	return nos, pts
 }
 
-func logf(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	pdk.Log(pdk.LogDebug, msg)
+// trendPoints 本组各单体的内阻变化率测点，之后为告警测点与告警单体数
+func (b group) trendPoints() []point.Point {
+	nos, irs := b.irCells()
+	out := make([]point.Point, 0, 2*len(nos)+1)
+	for i, no := range nos {
+		out = append(out, point.Point{Field: b.Prefix + "IRT" + battery.CellNo(no), Decimals: 1, RW: "R", Unit: "%",
+			Label: strings.Replace(irs[i].Label, "内阻", "内阻变化率", 1)})
+	}
+	for i, no := range nos {
+		out = append(out, point.Point{Field: b.Prefix + "IRA" + battery.CellNo(no), RW: "R",
+			Label: strings.Replace(irs[i].Label, "内阻", "内阻告警", 1), States: StatesIRAlarm})
+	}
+	return append(out, b.derived([]point.Point{{Field: "IR_ALARM_CNT", RW: "R", Label: "内阻告警单体数"}})...)
 }
 
-func hexPreview(b []byte, n int, max int) string {
-	if n <= 0 {
-		return ""
+// trackIR 更新各单体内阻的滚动平均与基准，返回各组的变化率、告警与告警单体数测点
+func trackIR(regs *point.Registers, cfg driver.Config, strs []group) []map[string]interface{} {
+	key := driver.StateKey(trendVar, cfg.DeviceAddress)
+	trends := map[string]battery.IRTrend{}
+	driver.LoadState(key, &trends)
+
+	window, err := configInt(cfg, "ir_window", DEFAULT_IR_WINDOW, 1, 10000)
+	if err != nil {
+		window = DEFAULT_IR_WINDOW
 	}
-	if n > len(b) {
-		n = len(b)
+	params := battery.IRParams{
+		Window:    window,
+		Growth:    configFloat(cfg, "ir_growth", DEFAULT_IR_GROWTH),
+		MedianDev: configFloat(cfg, "ir_median_dev", DEFAULT_IR_MEDIAN_DEV),
 	}
-	if n > max {
-		n = max
+
+	var out []map[string]interface{}
+	for _, b := range strs {
+		trend := trends[strconv.Itoa(b.No)]
+		if trend == nil {
+			trend = battery.IRTrend{}
+			trends[strconv.Itoa(b.No)] = trend
+		}
+		nos, _ := b.irCells()
+		status, count := trend.Track(params, cellValues(regs, b, "IR"), nos)
+
+		pts := b.trendPoints()
+		n := len(nos)
+		rates, alarms := make([]map[string]interface{}, 0, n), make([]map[string]interface{}, 0, n)
+		for i, st := range status {
+			rate, alarm := pts[i], pts[n+i]
+			if st.Reason != "" {
+				rates = append(rates, rate.Invalid(point.QualityNoData, st.Reason))
+				alarms = append(alarms, alarm.Invalid(point.QualityNoData, st.Reason))
+				continue
+			}
+			rates = append(rates, rate.Output(st.Rate))
+			alarms = append(alarms, alarm.Output(float64(st.Alarm)))
+		}
+		out = append(append(append(out, rates...), alarms...), pts[2*n].Output(float64(count)))
 	}
-	return fmt.Sprintf("% X", b[:n])
+	driver.SaveState(key, trends)
+	return out
 }
 
-func main() {}
+// =============================================================================
+// 【用户修改】命令
+// =============================================================================
+func runCommand(cfg driver.Config, strs []group, name string) map[string]interface{} {
+	if name != "reset_ir_baseline" {
+		return map[string]interface{}{"success": false, "error": "unsupported func_name: " + name}
+	}
+	key := driver.StateKey(trendVar, cfg.DeviceAddress)
+	if cfg.FieldName == "" {
+		driver.ClearState(key)
+		return map[string]interface{}{"success": true, "data": map[string]interface{}{"command": name, "field_name": ""}}
+	}
+
+	for _, b := range strs {
+		if _, ok := point.Find(b.Points, cfg.FieldName); !ok {
+			continue
+		}
+		kind, no, ok := cellOf(strings.TrimPrefix(cfg.FieldName, b.Prefix))
+		if !ok || kind != "IR" {
+			break
+		}
+		trends := map[string]battery.IRTrend{}
+		driver.LoadState(key, &trends)
+		trends[strconv.Itoa(b.No)].Reset(no)
+		driver.SaveState(key, trends)
+		return map[string]interface{}{"success": true, "data": map[string]interface{}{"command": name, "field_name": cfg.FieldName}}
+	}
+	return map[string]interface{}{"success": false, "error": "field_name must be a cell resistance point such as IR07: " + cfg.FieldName}
+}
 
+func main() {}